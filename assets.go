@@ -0,0 +1,39 @@
+package swagger
+
+import (
+	"embed"
+	"io/fs"
+	"net/http"
+)
+
+// SwaggerUIVersion is the version of the embedded swagger-ui-dist assets,
+// refreshed with `make swagger-ui`.
+const SwaggerUIVersion = "5.18.2"
+
+// assetsFS holds the swagger-ui-dist assets, along with their Apache-2.0
+// LICENSE and NOTICE which are served next to them.
+//
+//go:embed assets/swagger-ui
+var assetsFS embed.FS
+
+// swaggerUIAssets roots the embedded assets at their directory. fs.Sub only
+// fails on an invalid path, the embedded files are checked by the tests
+// serving them.
+var swaggerUIAssets, _ = fs.Sub(assetsFS, "assets/swagger-ui")
+
+// AssetSource selects where the documentation page loads its assets from.
+type AssetSource int
+
+const (
+	// EmbeddedAssets serves the assets bundled in the binary under the UI
+	// mount path, so the page works without internet access.
+	EmbeddedAssets AssetSource = iota
+	// CDNAssets loads the assets from jsDelivr. UIs other than Swagger UI
+	// always do.
+	CDNAssets
+)
+
+// assetsHandler serves the embedded Swagger UI assets, mounted at prefix.
+func assetsHandler(prefix string) http.Handler {
+	return http.StripPrefix(prefix, http.FileServer(http.FS(swaggerUIAssets)))
+}
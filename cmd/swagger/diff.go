@@ -0,0 +1,58 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/tinh-tinh/swagger/v2"
+)
+
+func runDiff(args []string) error {
+	flags := flag.NewFlagSet("diff", flag.ContinueOnError)
+	flags.Usage = func() {
+		fmt.Fprintln(flags.Output(), "Usage: swagger diff [flags] <base> <revision>")
+		flags.PrintDefaults()
+	}
+	breakingOnly := flags.Bool("breaking", false, "only print breaking changes")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if flags.NArg() != 2 {
+		flags.Usage()
+		return errors.New("diff takes the base and revision documents")
+	}
+
+	changes, err := swagger.CompareFiles(flags.Arg(0), flags.Arg(1))
+	if err != nil {
+		return err
+	}
+	return printChanges(os.Stdout, changes, *breakingOnly)
+}
+
+// breakingError reports breaking changes, for the command to exit with a
+// status telling them apart from failures.
+type breakingError struct {
+	count int
+}
+
+func (e *breakingError) Error() string {
+	return fmt.Sprintf("%d breaking change(s)", e.count)
+}
+
+// printChanges writes one change per line, and returns a *breakingError when
+// one of them is breaking.
+func printChanges(w io.Writer, changes swagger.Changes, breakingOnly bool) error {
+	if breakingOnly {
+		changes = changes.Breaking()
+	}
+	for _, change := range changes {
+		fmt.Fprintln(w, change)
+	}
+	if n := len(changes.Breaking()); n > 0 {
+		return &breakingError{count: n}
+	}
+	return nil
+}
@@ -0,0 +1,65 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/tinh-tinh/swagger/v2"
+)
+
+func Test_PrintChanges(t *testing.T) {
+	changes := swagger.Changes{
+		{Kind: swagger.ChangeEndpointAdded, Route: "GET /users", Message: "endpoint added"},
+		{Kind: swagger.ChangeEndpointRemoved, Breaking: true, Route: "DELETE /users/{id}", Message: "endpoint removed"},
+	}
+
+	var out bytes.Buffer
+	err := printChanges(&out, changes, false)
+	require.EqualError(t, err, "1 breaking change(s)")
+	require.Equal(t, 2, exitCode(err))
+	require.Equal(t, "non-breaking: GET /users: endpoint added\nbreaking: DELETE /users/{id}: endpoint removed\n", out.String())
+
+	out.Reset()
+	require.NotNil(t, printChanges(&out, changes, true))
+	require.Equal(t, "breaking: DELETE /users/{id}: endpoint removed\n", out.String())
+
+	out.Reset()
+	require.Nil(t, printChanges(&out, changes[:1], false))
+}
+
+func Test_DiffExitCode(t *testing.T) {
+	dir := t.TempDir()
+	base := filepath.Join(dir, "base.yaml")
+	revision := filepath.Join(dir, "revision.yaml")
+	require.Nil(t, os.WriteFile(base, []byte(`
+openapi: 3.0.0
+info: {title: Shop, version: "1.0"}
+paths:
+  /users:
+    get:
+      responses:
+        "200": {description: OK}
+    delete:
+      responses:
+        "204": {description: Deleted}
+`), 0o644))
+	require.Nil(t, os.WriteFile(revision, []byte(`
+openapi: 3.0.0
+info: {title: Shop, version: "1.0"}
+paths:
+  /users:
+    get:
+      responses:
+        "200": {description: OK}
+`), 0o644))
+
+	require.Equal(t, 2, exitCode(runDiff([]string{base, revision})))
+	require.Nil(t, runDiff([]string{revision, base}))
+	require.Equal(t, 1, exitCode(runDiff([]string{base, filepath.Join(dir, "missing.yaml")})))
+	require.Equal(t, 1, exitCode(runDiff([]string{base})))
+	require.Equal(t, 1, exitCode(errors.New("generating openapi.json: exit status 1")))
+}
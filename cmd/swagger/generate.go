@@ -0,0 +1,166 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"flag"
+	"fmt"
+	"go/format"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"text/template"
+)
+
+type generateOptions struct {
+	module string
+	spec   string
+	prefix string
+	output string
+	format string
+}
+
+func runGenerate(args []string) error {
+	var opt generateOptions
+	flags := flag.NewFlagSet("generate", flag.ContinueOnError)
+	flags.StringVar(&opt.module, "module", "", "app module function, as `import/path.Func` (required)")
+	flags.StringVar(&opt.spec, "spec", "", "function returning the *swagger.SpecBuilder to fill, as `import/path.Func`")
+	flags.StringVar(&opt.prefix, "prefix", "", "global prefix of the app")
+	flags.StringVar(&opt.output, "o", "openapi.json", "output `file`")
+	flags.StringVar(&opt.format, "format", "", "output format, json or yaml, inferred from the file extension by default")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if opt.module == "" {
+		flags.Usage()
+		return errors.New("-module is required")
+	}
+	if opt.format != "" && opt.format != "json" && opt.format != "yaml" {
+		return fmt.Errorf("unknown format %q", opt.format)
+	}
+
+	output, err := filepath.Abs(opt.output)
+	if err != nil {
+		return err
+	}
+	opt.output = output
+
+	source, err := generateMain(opt)
+	if err != nil {
+		return err
+	}
+
+	root, err := moduleRoot()
+	if err != nil {
+		return err
+	}
+	dir, err := os.MkdirTemp(root, ".swagger-generate-")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(dir)
+	if err := os.WriteFile(filepath.Join(dir, "main.go"), source, 0o644); err != nil {
+		return err
+	}
+
+	cmd := exec.Command("go", "run", "./"+filepath.Base(dir))
+	cmd.Dir = root
+	cmd.Stdout = os.Stderr
+	cmd.Stderr = os.Stderr
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("generating %s: %w", opt.output, err)
+	}
+	fmt.Println(opt.output)
+	return nil
+}
+
+// moduleRoot returns the directory of the go.mod file of the current
+// directory.
+func moduleRoot() (string, error) {
+	dir, err := os.Getwd()
+	if err != nil {
+		return "", err
+	}
+	for {
+		if _, err := os.Stat(filepath.Join(dir, "go.mod")); err == nil {
+			return dir, nil
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return "", errors.New("go.mod not found, run swagger from within the module of the app")
+		}
+		dir = parent
+	}
+}
+
+// splitTarget splits "import/path.Func" into its import path and function
+// name.
+func splitTarget(target string) (string, string, error) {
+	idx := strings.LastIndex(target, ".")
+	if idx <= strings.LastIndex(target, "/") || idx == len(target)-1 {
+		return "", "", fmt.Errorf("%q is not of the form import/path.Func", target)
+	}
+	return target[:idx], target[idx+1:], nil
+}
+
+var mainTemplate = template.Must(template.New("main").Parse(`// Code generated by swagger generate. DO NOT EDIT.
+
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/tinh-tinh/swagger/v2"
+	app {{printf "%q" .ModulePkg}}
+	{{- if and .SpecPkg (ne .SpecPkg .ModulePkg)}}
+	docs {{printf "%q" .SpecPkg}}
+	{{- end}}
+)
+
+func main() {
+	{{- if .SpecFunc}}
+	spec := {{if eq .SpecPkg .ModulePkg}}app{{else}}docs{{end}}.{{.SpecFunc}}()
+	{{- else}}
+	spec := swagger.NewSpecBuilder()
+	{{- end}}
+	err := swagger.Generate(app.{{.ModuleFunc}}, spec, {{printf "%q" .Output}}, swagger.GenerateOptions{
+		Prefix: {{printf "%q" .Prefix}},
+		Format: swagger.Format({{printf "%q" .Format}}),
+	})
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
+`))
+
+// generateMain returns the source of the main package generating the
+// document.
+func generateMain(opt generateOptions) ([]byte, error) {
+	data := struct {
+		ModulePkg, ModuleFunc string
+		SpecPkg, SpecFunc     string
+		Prefix, Output        string
+		Format                string
+	}{Prefix: opt.prefix, Output: opt.output, Format: opt.format}
+
+	var err error
+	data.ModulePkg, data.ModuleFunc, err = splitTarget(opt.module)
+	if err != nil {
+		return nil, err
+	}
+	if opt.spec != "" {
+		data.SpecPkg, data.SpecFunc, err = splitTarget(opt.spec)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	var source bytes.Buffer
+	if err := mainTemplate.Execute(&source, data); err != nil {
+		return nil, err
+	}
+	return format.Source(source.Bytes())
+}
@@ -0,0 +1,40 @@
+package main
+
+import (
+	"go/parser"
+	"go/token"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_SplitTarget(t *testing.T) {
+	pkg, fn, err := splitTarget("github.com/acme/shop.v2/app.AppModule")
+	require.Nil(t, err)
+	require.Equal(t, "github.com/acme/shop.v2/app", pkg)
+	require.Equal(t, "AppModule", fn)
+
+	for _, target := range []string{"AppModule", "github.com/acme/shop.v2/app", "github.com/acme/app."} {
+		_, _, err := splitTarget(target)
+		require.NotNil(t, err, target)
+	}
+}
+
+func Test_GenerateMain(t *testing.T) {
+	testCases := []struct {
+		opt     generateOptions
+		imports int
+	}{
+		{generateOptions{module: "example.com/shop/app.AppModule", output: "/tmp/openapi.json"}, 4},
+		{generateOptions{module: "example.com/shop/app.AppModule", spec: "example.com/shop/app.Spec", output: "/tmp/openapi.json"}, 4},
+		{generateOptions{module: "example.com/shop/app.AppModule", spec: "example.com/shop/docs.Spec", prefix: "api", output: "/tmp/openapi.yaml", format: "yaml"}, 5},
+	}
+	for _, tc := range testCases {
+		source, err := generateMain(tc.opt)
+		require.Nil(t, err)
+		file, err := parser.ParseFile(token.NewFileSet(), "main.go", source, 0)
+		require.Nil(t, err)
+		require.Len(t, file.Imports, tc.imports)
+		require.Contains(t, string(source), "swagger.Generate(app.AppModule, spec, \""+tc.opt.output+"\"")
+	}
+}
@@ -0,0 +1,67 @@
+// Command swagger generates the OpenAPI document of a tinhtinh app without
+// booting its server, e.g. to commit it from CI, and compares it with a
+// baseline to detect breaking changes.
+//
+// Usage:
+//
+//	swagger generate -module github.com/acme/shop/app.AppModule -prefix api -o openapi.yaml
+//	swagger diff docs/openapi.yaml openapi.yaml
+//
+// The module function, and the optional -spec function returning the
+// *swagger.SpecBuilder to fill, are built in a temporary main package inside
+// the module of the current directory and run with `go run`.
+//
+// diff prints the changes from the base document to the revision, and exits
+// with status 2 when one of them is breaking. Every command exits with status
+// 1 when it fails.
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+)
+
+const usage = `Usage: swagger <command> [flags]
+
+Commands:
+  generate  write the OpenAPI document of an app module to a file
+  diff      compare two documents and fail on breaking changes
+
+Run "swagger <command> -h" for the flags of a command.
+`
+
+func main() {
+	if len(os.Args) < 2 {
+		fmt.Fprint(os.Stderr, usage)
+		os.Exit(1)
+	}
+
+	var err error
+	switch os.Args[1] {
+	case "generate":
+		err = runGenerate(os.Args[2:])
+	case "diff":
+		err = runDiff(os.Args[2:])
+	case "-h", "-help", "--help", "help":
+		fmt.Print(usage)
+		return
+	default:
+		fmt.Fprintf(os.Stderr, "swagger: unknown command %q\n\n%s", os.Args[1], usage)
+		os.Exit(1)
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "swagger:", err)
+		os.Exit(exitCode(err))
+	}
+}
+
+// exitCode returns 2 for breaking changes and 1 for any other error, so that
+// CI can tell breaking changes from failures of the command.
+func exitCode(err error) int {
+	var breaking *breakingError
+	if errors.As(err, &breaking) {
+		return 2
+	}
+	return 1
+}
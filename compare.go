@@ -0,0 +1,441 @@
+package swagger
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"os"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+const (
+	ChangeEndpointRemoved   = "endpoint_removed"
+	ChangeEndpointAdded     = "endpoint_added"
+	ChangeParameterRemoved  = "parameter_removed"
+	ChangeParameterAdded    = "parameter_added"
+	ChangeParameterRequired = "parameter_required"
+	ChangeParameterOptional = "parameter_optional"
+	ChangeBodyAdded         = "request_body_added"
+	ChangeBodyRemoved       = "request_body_removed"
+	ChangeBodyRequired      = "request_body_required"
+	ChangeMediaTypeRemoved  = "media_type_removed"
+	ChangeMediaTypeAdded    = "media_type_added"
+	ChangeResponseRemoved   = "response_removed"
+	ChangeResponseAdded     = "response_added"
+	ChangePropertyRemoved   = "property_removed"
+	ChangePropertyAdded     = "property_added"
+	ChangePropertyRequired  = "property_required"
+	ChangePropertyOptional  = "property_optional"
+	ChangeTypeChanged       = "type_changed"
+	ChangeEnumNarrowed      = "enum_narrowed"
+	ChangeEnumWidened       = "enum_widened"
+)
+
+// Change is a difference between two versions of a document. Breaking
+// changes may fail clients written against the base version: e.g. a removed
+// endpoint, a new required field or parameter, a narrowed request enum or a
+// changed type.
+type Change struct {
+	Kind     string
+	Breaking bool
+	// Route is the operation changed, e.g. "GET /api/users/{id}".
+	Route string
+	// Location is the part of the operation changed, e.g.
+	// "request body.address.city", empty for the whole operation.
+	Location string
+	Message  string
+}
+
+func (c Change) String() string {
+	level := "non-breaking"
+	if c.Breaking {
+		level = "breaking"
+	}
+	if c.Location == "" {
+		return fmt.Sprintf("%s: %s: %s", level, c.Route, c.Message)
+	}
+	return fmt.Sprintf("%s: %s: %s: %s", level, c.Route, c.Location, c.Message)
+}
+
+// Changes lists the differences between two documents, by path and method.
+type Changes []Change
+
+// Breaking returns the breaking changes.
+func (changes Changes) Breaking() Changes {
+	var breaking Changes
+	for _, c := range changes {
+		if c.Breaking {
+			breaking = append(breaking, c)
+		}
+	}
+	return breaking
+}
+
+// HasBreaking reports whether one of the changes is breaking.
+func (changes Changes) HasBreaking() bool {
+	return slices.ContainsFunc(changes, func(c Change) bool { return c.Breaking })
+}
+
+// CompareDocuments compares the revision of a JSON or YAML document with its
+// base version, e.g. the document committed in the repository.
+func CompareDocuments(base, revision []byte) (Changes, error) {
+	baseDoc, err := loadDocument(base)
+	if err != nil {
+		return nil, fmt.Errorf("base: %w", err)
+	}
+	revisionDoc, err := loadDocument(revision)
+	if err != nil {
+		return nil, fmt.Errorf("revision: %w", err)
+	}
+	return compare(baseDoc, revisionDoc), nil
+}
+
+// CompareFiles compares the document at revision with the one at base.
+func CompareFiles(base, revision string) (Changes, error) {
+	baseData, err := os.ReadFile(base)
+	if err != nil {
+		return nil, err
+	}
+	revisionData, err := os.ReadFile(revision)
+	if err != nil {
+		return nil, err
+	}
+	return CompareDocuments(baseData, revisionData)
+}
+
+// CompareFile compares the document built by ParsePaths with the baseline
+// document at path, so that a test can fail on breaking changes:
+//
+//	changes, err := spec.CompareFile("docs/openapi.yaml")
+//	require.Empty(t, changes.Breaking())
+func (spec *SpecBuilder) CompareFile(path string) (Changes, error) {
+	base, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	revision, err := spec.Document()
+	if err != nil {
+		return nil, err
+	}
+	return CompareDocuments(base, revision)
+}
+
+func loadDocument(data []byte) (*openapi3.T, error) {
+	loader := &openapi3.Loader{Context: context.Background()}
+	return loader.LoadFromData(data)
+}
+
+var pathParamRegexp = regexp.MustCompile(`\{[^}]*\}`)
+
+// comparer collects the changes between two documents.
+type comparer struct {
+	changes Changes
+	route   string
+	// request is set while comparing schemas sent by the client, for which
+	// narrowing is breaking, as opposed to schemas of responses, for which
+	// widening is.
+	request bool
+	seen    map[[2]*openapi3.Schema]bool
+}
+
+func (c *comparer) add(kind string, breaking bool, location, format string, args ...any) {
+	c.changes = append(c.changes, Change{
+		Kind:     kind,
+		Breaking: breaking,
+		Route:    c.route,
+		Location: location,
+		Message:  fmt.Sprintf(format, args...),
+	})
+}
+
+func compare(base, revision *openapi3.T) Changes {
+	c := &comparer{}
+
+	// Paths only differing by the names of their parameters are the same
+	// endpoint.
+	paths := func(doc *openapi3.T) map[string]string {
+		keys := map[string]string{}
+		if doc.Paths != nil {
+			for path := range doc.Paths.Map() {
+				keys[pathParamRegexp.ReplaceAllString(path, "{}")] = path
+			}
+		}
+		return keys
+	}
+	basePaths, revisionPaths := paths(base), paths(revision)
+
+	keys := maps.Clone(basePaths)
+	maps.Copy(keys, revisionPaths)
+	for _, key := range slices.Sorted(maps.Keys(keys)) {
+		var baseOps, revisionOps map[string]*openapi3.Operation
+		path := revisionPaths[key]
+		if p, ok := basePaths[key]; ok {
+			baseOps = base.Paths.Value(p).Operations()
+			if path == "" {
+				path = p
+			}
+		}
+		if p, ok := revisionPaths[key]; ok {
+			revisionOps = revision.Paths.Value(p).Operations()
+		}
+
+		methods := maps.Clone(baseOps)
+		if methods == nil {
+			methods = map[string]*openapi3.Operation{}
+		}
+		maps.Copy(methods, revisionOps)
+		for _, method := range slices.Sorted(maps.Keys(methods)) {
+			c.route = method + " " + path
+			baseOp, revisionOp := baseOps[method], revisionOps[method]
+			switch {
+			case revisionOp == nil:
+				c.add(ChangeEndpointRemoved, true, "", "endpoint removed")
+			case baseOp == nil:
+				c.add(ChangeEndpointAdded, false, "", "endpoint added")
+			default:
+				c.operation(baseOp, revisionOp)
+			}
+		}
+	}
+	return c.changes
+}
+
+func (c *comparer) operation(base, revision *openapi3.Operation) {
+	c.request = true
+	c.parameters(base.Parameters, revision.Parameters)
+	c.requestBody(base.RequestBody, revision.RequestBody)
+	c.request = false
+	c.responses(base.Responses, revision.Responses)
+}
+
+func (c *comparer) parameters(base, revision openapi3.Parameters) {
+	params := func(list openapi3.Parameters) map[string]*openapi3.Parameter {
+		m := map[string]*openapi3.Parameter{}
+		for _, ref := range list {
+			if ref != nil && ref.Value != nil {
+				m[ref.Value.In+" parameter "+ref.Value.Name] = ref.Value
+			}
+		}
+		return m
+	}
+	baseParams, revisionParams := params(base), params(revision)
+
+	for _, name := range slices.Sorted(maps.Keys(baseParams)) {
+		baseParam, revisionParam := baseParams[name], revisionParams[name]
+		switch {
+		case revisionParam == nil && baseParam.In == openapi3.ParameterInPath:
+			// Path parameters are part of the endpoint, and are only renamed
+			// along with the path.
+		case revisionParam == nil:
+			c.add(ChangeParameterRemoved, false, name, "parameter removed")
+		case !baseParam.Required && revisionParam.Required:
+			c.add(ChangeParameterRequired, true, name, "parameter became required")
+		case baseParam.Required && !revisionParam.Required:
+			c.add(ChangeParameterOptional, false, name, "parameter became optional")
+		}
+		if revisionParam != nil {
+			c.schema(name, baseParam.Schema, revisionParam.Schema)
+		}
+	}
+	for _, name := range slices.Sorted(maps.Keys(revisionParams)) {
+		if _, ok := baseParams[name]; ok || revisionParams[name].In == openapi3.ParameterInPath {
+			continue
+		}
+		if revisionParams[name].Required {
+			c.add(ChangeParameterAdded, true, name, "new required parameter")
+		} else {
+			c.add(ChangeParameterAdded, false, name, "new optional parameter")
+		}
+	}
+}
+
+func (c *comparer) requestBody(base, revision *openapi3.RequestBodyRef) {
+	const location = "request body"
+	var baseBody, revisionBody *openapi3.RequestBody
+	if base != nil {
+		baseBody = base.Value
+	}
+	if revision != nil {
+		revisionBody = revision.Value
+	}
+
+	switch {
+	case baseBody == nil && revisionBody == nil:
+		return
+	case baseBody == nil:
+		if revisionBody.Required {
+			c.add(ChangeBodyAdded, true, location, "new required request body")
+		} else {
+			c.add(ChangeBodyAdded, false, location, "new optional request body")
+		}
+		return
+	case revisionBody == nil:
+		c.add(ChangeBodyRemoved, false, location, "request body removed")
+		return
+	case !baseBody.Required && revisionBody.Required:
+		c.add(ChangeBodyRequired, true, location, "request body became required")
+	}
+	c.content(location, baseBody.Content, revisionBody.Content)
+}
+
+func (c *comparer) responses(base, revision *openapi3.Responses) {
+	var baseResponses, revisionResponses map[string]*openapi3.ResponseRef
+	if base != nil {
+		baseResponses = base.Map()
+	}
+	if revision != nil {
+		revisionResponses = revision.Map()
+	}
+
+	for _, status := range slices.Sorted(maps.Keys(baseResponses)) {
+		location := "response " + status
+		revisionResponse, ok := revisionResponses[status]
+		if !ok {
+			// Clients only rely on the responses they expect to succeed.
+			success := strings.HasPrefix(status, "2") || status == "default"
+			c.add(ChangeResponseRemoved, success, location, "response removed")
+			continue
+		}
+		if baseResponses[status].Value == nil || revisionResponse.Value == nil {
+			continue
+		}
+		c.content(location, baseResponses[status].Value.Content, revisionResponse.Value.Content)
+	}
+	for _, status := range slices.Sorted(maps.Keys(revisionResponses)) {
+		if _, ok := baseResponses[status]; !ok {
+			c.add(ChangeResponseAdded, false, "response "+status, "response added")
+		}
+	}
+}
+
+func (c *comparer) content(location string, base, revision openapi3.Content) {
+	for _, mediaType := range slices.Sorted(maps.Keys(base)) {
+		revisionMedia, ok := revision[mediaType]
+		if !ok {
+			c.add(ChangeMediaTypeRemoved, true, location, "media type %s removed", mediaType)
+			continue
+		}
+		if base[mediaType] != nil && revisionMedia != nil {
+			c.schema(location, base[mediaType].Schema, revisionMedia.Schema)
+		}
+	}
+	for _, mediaType := range slices.Sorted(maps.Keys(revision)) {
+		if _, ok := base[mediaType]; !ok {
+			c.add(ChangeMediaTypeAdded, false, location, "media type %s added", mediaType)
+		}
+	}
+}
+
+func (c *comparer) schema(location string, baseRef, revisionRef *openapi3.SchemaRef) {
+	if baseRef == nil || revisionRef == nil || baseRef.Value == nil || revisionRef.Value == nil {
+		return
+	}
+	base, revision := baseRef.Value, revisionRef.Value
+
+	// Recursive schemas are compared once per pair.
+	pair := [2]*openapi3.Schema{base, revision}
+	if c.seen == nil {
+		c.seen = map[[2]*openapi3.Schema]bool{}
+	}
+	if c.seen[pair] {
+		return
+	}
+	c.seen[pair] = true
+	defer delete(c.seen, pair)
+
+	baseType, revisionType := typeOf(base), typeOf(revision)
+	if baseType != "" && revisionType != "" && baseType != revisionType {
+		c.add(ChangeTypeChanged, true, location, "type changed from %s to %s", baseType, revisionType)
+		return
+	}
+
+	c.enum(location, base.Enum, revision.Enum)
+
+	for _, name := range slices.Sorted(maps.Keys(base.Properties)) {
+		property := location + "." + name
+		revisionProperty, ok := revision.Properties[name]
+		if !ok {
+			c.add(ChangePropertyRemoved, !c.request, property, "property removed")
+			continue
+		}
+		baseRequired, revisionRequired := slices.Contains(base.Required, name), slices.Contains(revision.Required, name)
+		switch {
+		case !baseRequired && revisionRequired:
+			c.add(ChangePropertyRequired, c.request, property, "property became required")
+		case baseRequired && !revisionRequired:
+			c.add(ChangePropertyOptional, !c.request, property, "property became optional")
+		}
+		c.schema(property, base.Properties[name], revisionProperty)
+	}
+	for _, name := range slices.Sorted(maps.Keys(revision.Properties)) {
+		if _, ok := base.Properties[name]; ok {
+			continue
+		}
+		if slices.Contains(revision.Required, name) {
+			c.add(ChangePropertyAdded, c.request, location+"."+name, "new required property")
+		} else {
+			c.add(ChangePropertyAdded, false, location+"."+name, "new optional property")
+		}
+	}
+
+	c.schema(location+"[]", base.Items, revision.Items)
+}
+
+// enum records the values removed from or added to an enum. An enum added to
+// a schema narrows it, and an enum removed widens it.
+func (c *comparer) enum(location string, base, revision []any) {
+	if len(base) == 0 && len(revision) == 0 {
+		return
+	}
+	if len(base) == 0 {
+		c.add(ChangeEnumNarrowed, c.request, location, "values restricted to %s", enumString(revision))
+		return
+	}
+	if len(revision) == 0 {
+		c.add(ChangeEnumWidened, !c.request, location, "values no longer restricted")
+		return
+	}
+
+	removed, added := enumDiff(base, revision), enumDiff(revision, base)
+	if len(removed) > 0 {
+		c.add(ChangeEnumNarrowed, c.request, location, "enum values %s removed", enumString(removed))
+	}
+	if len(added) > 0 {
+		c.add(ChangeEnumWidened, !c.request, location, "enum values %s added", enumString(added))
+	}
+}
+
+// enumDiff returns the values of a missing from b.
+func enumDiff(a, b []any) []any {
+	values := map[string]bool{}
+	for _, v := range b {
+		values[enumString([]any{v})] = true
+	}
+	var diff []any
+	for _, v := range a {
+		if !values[enumString([]any{v})] {
+			diff = append(diff, v)
+		}
+	}
+	return diff
+}
+
+func enumString(values []any) string {
+	data, err := json.Marshal(values)
+	if err != nil {
+		return fmt.Sprint(values)
+	}
+	return string(data)
+}
+
+func typeOf(schema *openapi3.Schema) string {
+	if schema.Type == nil {
+		return ""
+	}
+	return strings.Join(schema.Type.Slice(), ",")
+}
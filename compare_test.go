@@ -0,0 +1,164 @@
+package swagger_test
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/tinh-tinh/swagger/v2"
+	"github.com/tinh-tinh/tinhtinh/v2/core"
+)
+
+const baseDocument = `
+openapi: 3.0.0
+info: {title: Shop, version: "1.0"}
+paths:
+  /users/{id}:
+    get:
+      parameters:
+        - {name: id, in: path, required: true, schema: {type: string}}
+        - {name: fields, in: query, schema: {type: string}}
+      responses:
+        "200":
+          description: OK
+          content:
+            application/json:
+              schema: {$ref: "#/components/schemas/User"}
+        "404": {description: Not found}
+    delete:
+      responses:
+        "204": {description: Deleted}
+  /users:
+    post:
+      requestBody:
+        content:
+          application/json:
+            schema:
+              type: object
+              required: [name]
+              properties:
+                name: {type: string}
+                role: {type: string, enum: [admin, member, guest]}
+      responses:
+        "201": {description: Created}
+components:
+  schemas:
+    User:
+      type: object
+      required: [id, name]
+      properties:
+        id: {type: integer}
+        name: {type: string}
+        status: {type: string, enum: [active, blocked]}
+`
+
+const revisionDocument = `
+openapi: 3.0.0
+info: {title: Shop, version: "2.0"}
+paths:
+  /users/{userId}:
+    get:
+      parameters:
+        - {name: userId, in: path, required: true, schema: {type: string}}
+        - {name: fields, in: query, required: true, schema: {type: string}}
+        - {name: expand, in: query, schema: {type: boolean}}
+      responses:
+        "200":
+          description: OK
+          content:
+            application/json:
+              schema: {$ref: "#/components/schemas/User"}
+  /users:
+    post:
+      requestBody:
+        content:
+          application/json:
+            schema:
+              type: object
+              required: [name, email]
+              properties:
+                name: {type: string}
+                email: {type: string}
+                role: {type: string, enum: [admin, member, owner]}
+      responses:
+        "201": {description: Created}
+    get:
+      responses:
+        "200": {description: OK}
+components:
+  schemas:
+    User:
+      type: object
+      required: [id]
+      properties:
+        id: {type: string}
+        name: {type: string}
+        status: {type: string, enum: [active, blocked, deleted]}
+`
+
+func Test_CompareDocuments(t *testing.T) {
+	changes, err := swagger.CompareDocuments([]byte(baseDocument), []byte(revisionDocument))
+	require.Nil(t, err)
+
+	type change struct {
+		Kind     string
+		Breaking bool
+		Route    string
+		Location string
+	}
+	var got []change
+	for _, c := range changes {
+		got = append(got, change{c.Kind, c.Breaking, c.Route, c.Location})
+	}
+	require.Equal(t, []change{
+		{swagger.ChangeEndpointAdded, false, "GET /users", ""},
+		{swagger.ChangeEnumNarrowed, true, "POST /users", "request body.role"},
+		{swagger.ChangeEnumWidened, false, "POST /users", "request body.role"},
+		{swagger.ChangePropertyAdded, true, "POST /users", "request body.email"},
+		{swagger.ChangeEndpointRemoved, true, "DELETE /users/{userId}", ""},
+		{swagger.ChangeParameterRequired, true, "GET /users/{userId}", "query parameter fields"},
+		{swagger.ChangeParameterAdded, false, "GET /users/{userId}", "query parameter expand"},
+		{swagger.ChangeTypeChanged, true, "GET /users/{userId}", "response 200.id"},
+		{swagger.ChangePropertyOptional, true, "GET /users/{userId}", "response 200.name"},
+		{swagger.ChangeEnumWidened, true, "GET /users/{userId}", "response 200.status"},
+		{swagger.ChangeResponseRemoved, false, "GET /users/{userId}", "response 404"},
+	}, got)
+	require.True(t, changes.HasBreaking())
+	require.Len(t, changes.Breaking(), 7)
+	require.Equal(t, `breaking: POST /users: request body.role: enum values ["guest"] removed`, changes[1].String())
+
+	changes, err = swagger.CompareDocuments([]byte(baseDocument), []byte(baseDocument))
+	require.Nil(t, err)
+	require.Empty(t, changes)
+
+	_, err = swagger.CompareDocuments([]byte(baseDocument), []byte("paths: ["))
+	require.NotNil(t, err)
+}
+
+func Test_CompareFile(t *testing.T) {
+	app := core.CreateFactory(AppModule)
+	app.SetGlobalPrefix("api")
+
+	spec := swagger.NewSpecBuilder()
+	spec.ParsePaths(app)
+	path := filepath.Join(t.TempDir(), "openapi.yaml")
+	require.Nil(t, spec.WriteFile(path))
+
+	changes, err := spec.CompareFile(path)
+	require.Nil(t, err)
+	require.Empty(t, changes)
+
+	delete(spec.Paths, "/api/users")
+	changes, err = spec.CompareFile(path)
+	require.Nil(t, err)
+	require.True(t, changes.HasBreaking())
+	require.Equal(t, swagger.ChangeEndpointRemoved, changes[0].Kind)
+	require.Equal(t, "GET /api/users", changes[0].Route)
+
+	base := filepath.Join(t.TempDir(), "openapi.json")
+	require.Nil(t, spec.WriteFile(base))
+	changes, err = swagger.CompareFiles(base, path)
+	require.Nil(t, err)
+	require.False(t, changes.HasBreaking())
+	require.Equal(t, swagger.ChangeEndpointAdded, changes[0].Kind)
+}
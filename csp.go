@@ -0,0 +1,30 @@
+package swagger
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"fmt"
+	"net/url"
+)
+
+// newNonce returns a random Content-Security-Policy nonce.
+func newNonce() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// defaultContentSecurityPolicy allows the page to load its assets and the
+// document from the app, and its assets from the CDN when they come from
+// there. UIs inject styles at runtime, so inline styles stay allowed.
+func defaultContentSecurityPolicy(assets string) string {
+	var cdn string
+	if u, err := url.Parse(assets); err == nil && u.Host != "" {
+		cdn = " " + u.Scheme + "://" + u.Host
+	}
+	return fmt.Sprintf("default-src 'self'; script-src 'self' 'nonce-{nonce}'%[1]s; "+
+		"style-src 'self' 'unsafe-inline'%[1]s; img-src 'self' data: https:; font-src 'self' data:%[1]s; "+
+		"connect-src 'self'; object-src 'none'; base-uri 'self'", cdn)
+}
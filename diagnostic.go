@@ -0,0 +1,16 @@
+package swagger
+
+const (
+	DiagnosticSchemaCollision = "schema_collision"
+	DiagnosticExampleEncoding = "example_encoding"
+	DiagnosticRouteConflict   = "route_conflict"
+	DiagnosticVersioning      = "versioning"
+)
+
+// Diagnostic reports a problem found while building the document which did
+// not prevent the document from being generated.
+type Diagnostic struct {
+	Kind    string
+	Route   string
+	Message string
+}
@@ -0,0 +1,11 @@
+package swagger
+
+// ReadVersioning and ReadEncoder expose the readers of unexported core.App
+// fields to the tests of package swagger_test.
+var (
+	ReadVersioning = readVersioning
+	ReadEncoder    = readEncoder
+)
+
+// AcceptsYAML exposes acceptsYAML to the tests of package swagger_test.
+var AcceptsYAML = acceptsYAML
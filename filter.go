@@ -0,0 +1,123 @@
+package swagger
+
+import (
+	"reflect"
+	"slices"
+	"strings"
+
+	"github.com/tinh-tinh/tinhtinh/v2/core"
+)
+
+// RouteFilter reports whether a route matches, to select the routes a
+// document describes.
+type RouteFilter func(route *core.Router) bool
+
+// Include restricts the document to the routes matching any of the given
+// filters. Several calls add alternatives, and a document without filters
+// describes every route:
+//
+//	partner := swagger.NewSpecBuilder().
+//		Include(swagger.ByPathPrefix("/partner"), swagger.ByTag("Partner"))
+func (spec *SpecBuilder) Include(filters ...RouteFilter) *SpecBuilder {
+	spec.include = append(spec.include, filters...)
+	return spec
+}
+
+// Filter hides the routes for which fn returns false from the document.
+// Routes are documented when they pass every filter, besides matching the
+// Include filters:
+//
+//	spec.Filter(func(route *core.Router) bool {
+//		return !strings.HasPrefix(route.Path, "webhooks")
+//	})
+func (spec *SpecBuilder) Filter(fn RouteFilter) *SpecBuilder {
+	spec.filters = append(spec.filters, fn)
+	return spec
+}
+
+// documents reports whether the document describes the route.
+func (spec *SpecBuilder) documents(route *core.Router) bool {
+	if idx := findMetadata(route.Metadata, EXCLUDE); idx != -1 {
+		if exclude, _ := route.Metadata[idx].Value.(bool); exclude {
+			return false
+		}
+	}
+	for _, filter := range spec.filters {
+		if !filter(route) {
+			return false
+		}
+	}
+	if len(spec.include) == 0 {
+		return true
+	}
+	return slices.ContainsFunc(spec.include, func(filter RouteFilter) bool {
+		return filter(route)
+	})
+}
+
+// ByPathPrefix matches routes whose path, below the global prefix, starts
+// with one of the given prefixes, e.g. "/admin" for /api/admin/users.
+func ByPathPrefix(prefixes ...string) RouteFilter {
+	return func(route *core.Router) bool {
+		parseRoute := core.ParseRoute(route.Method + " " + route.Path)
+		parseRoute.SetPrefix(route.Name)
+		return slices.ContainsFunc(prefixes, func(prefix string) bool {
+			prefix = core.IfSlashPrefixString(prefix)
+			return parseRoute.Path == prefix || strings.HasPrefix(parseRoute.Path, prefix+"/")
+		})
+	}
+}
+
+// ByTag matches routes tagged with one of the given tags.
+func ByTag(tags ...string) RouteFilter {
+	return func(route *core.Router) bool {
+		idx := findMetadata(route.Metadata, TAG)
+		if idx == -1 {
+			return false
+		}
+		names, _ := route.Metadata[idx].Value.([]string)
+		return slices.ContainsFunc(names, func(name string) bool {
+			return slices.Contains(tags, name)
+		})
+	}
+}
+
+// ByMetadata matches routes with the given metadata key, set to one of the
+// given values when there are any:
+//
+//	spec.Include(swagger.ByMetadata("audience", "partner"))
+func ByMetadata(key string, values ...interface{}) RouteFilter {
+	return func(route *core.Router) bool {
+		idx := findMetadata(route.Metadata, key)
+		if idx == -1 {
+			return false
+		}
+		if len(values) == 0 {
+			return true
+		}
+		return slices.ContainsFunc(values, func(value interface{}) bool {
+			return reflect.DeepEqual(route.Metadata[idx].Value, value)
+		})
+	}
+}
+
+// ByController matches routes of the controllers with the given names.
+func ByController(names ...string) RouteFilter {
+	return func(route *core.Router) bool {
+		return slices.ContainsFunc(names, func(name string) bool {
+			return strings.EqualFold(name, route.Name)
+		})
+	}
+}
+
+// ByModule matches routes of the modules named with ApiModule.
+func ByModule(names ...string) RouteFilter {
+	return func(route *core.Router) bool {
+		idx := findMetadata(route.Metadata, MODULE)
+		if idx == -1 {
+			return false
+		}
+		name, _ := route.Metadata[idx].Value.(string)
+		return slices.Contains(names, name)
+	}
+}
@@ -0,0 +1,219 @@
+package swagger_test
+
+import (
+	"encoding/json"
+	"io"
+	"maps"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/tinh-tinh/swagger/v2"
+	"github.com/tinh-tinh/tinhtinh/v2/core"
+)
+
+func documentedPaths(t *testing.T, filters ...swagger.RouteFilter) []string {
+	app := core.CreateFactory(AppModule)
+	app.SetGlobalPrefix("api")
+
+	spec := swagger.NewSpecBuilder().Include(filters...)
+	spec.ParsePaths(app)
+	return slices.Sorted(maps.Keys(spec.Paths))
+}
+
+func Test_Include(t *testing.T) {
+	require.Equal(t, []string{"/api/auth", "/api/posts", "/api/posts/{id}", "/api/users"}, documentedPaths(t))
+	require.Equal(t, []string{"/api/posts", "/api/posts/{id}"}, documentedPaths(t, swagger.ByTag("Post")))
+	require.Equal(t, []string{"/api/auth", "/api/users"}, documentedPaths(t, swagger.ByPathPrefix("/auth"), swagger.ByPathPrefix("users/")))
+	require.Empty(t, documentedPaths(t, swagger.ByPathPrefix("/post")))
+	require.Equal(t, []string{"/api/users"}, documentedPaths(t, swagger.ByController("Users")))
+	require.Equal(t, []string{"/api/posts/{id}"}, documentedPaths(t, swagger.ByMetadata(swagger.OK_RESPONSE)))
+	require.Equal(t, []string{"/api/users"}, documentedPaths(t, swagger.ByMetadata(swagger.SECURITY, []string{"bearerAuth"})))
+	require.Empty(t, documentedPaths(t, swagger.ByMetadata(swagger.SECURITY, []string{"oauth2"})))
+}
+
+func Test_ByModule(t *testing.T) {
+	appModule := func() core.Module {
+		return core.NewModule(core.NewModuleOptions{
+			Imports: []core.Modules{
+				UserModule,
+				func(module core.Module) core.Module {
+					return swagger.ModuleMetadata(PostModule(module), swagger.ApiModule("posts"))
+				},
+			},
+		})
+	}
+	app := core.CreateFactory(appModule)
+	app.SetGlobalPrefix("api")
+
+	spec := swagger.NewSpecBuilder().Include(swagger.ByModule("posts"))
+	spec.ParsePaths(app)
+	require.Equal(t, []string{"/api/posts", "/api/posts/{id}"}, slices.Sorted(maps.Keys(spec.Paths)))
+}
+
+func Test_SetUpDocuments(t *testing.T) {
+	server := core.CreateFactory(AppModule)
+	server.SetGlobalPrefix("api")
+
+	swagger.SetUpDocuments("/docs", server, []swagger.Document{
+		{Name: "Public API", Spec: swagger.NewSpecBuilder().Include(swagger.ByTag("Post"))},
+		{Name: "Accounts", Spec: swagger.NewSpecBuilder().Include(swagger.ByTag("Auth", "User"))},
+	})
+	testServer := httptest.NewServer(server.PrepareBeforeListen())
+	defer testServer.Close()
+
+	testClient := testServer.Client()
+	documents := map[string][]string{
+		"/api/docs/public-api/openapi.json": {"/api/posts", "/api/posts/{id}"},
+		"/api/docs/accounts/openapi.json":   {"/api/auth", "/api/users"},
+	}
+	for path, paths := range documents {
+		resp, err := testClient.Get(testServer.URL + path)
+		require.Nil(t, err)
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+
+		var doc struct {
+			Paths map[string]any `json:"paths"`
+		}
+		require.Nil(t, json.NewDecoder(resp.Body).Decode(&doc))
+		require.Equal(t, paths, slices.Sorted(maps.Keys(doc.Paths)))
+	}
+
+	resp, err := testClient.Get(testServer.URL + "/api/docs/accounts/openapi.yaml")
+	require.Nil(t, err)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+
+	resp, err = testClient.Get(testServer.URL + "/api/docs")
+	require.Nil(t, err)
+	data, err := io.ReadAll(resp.Body)
+	require.Nil(t, err)
+	require.Contains(t, string(data), `&#34;urls&#34;:[{&#34;name&#34;:&#34;Public API&#34;,&#34;url&#34;:&#34;/api/docs/public-api/openapi.json&#34;},{&#34;name&#34;:&#34;Accounts&#34;,&#34;url&#34;:&#34;/api/docs/accounts/openapi.json&#34;}]`)
+
+	// Swagger UI only reads urls in the top bar of the standalone layout,
+	// from the global preset of the embedded assets
+	resp, err = testClient.Get(testServer.URL + "/api/docs/init.js")
+	require.Nil(t, err)
+	data, err = io.ReadAll(resp.Body)
+	require.Nil(t, err)
+	require.Contains(t, string(data), `[SwaggerUIBundle.presets.apis, SwaggerUIStandalonePreset]`)
+	require.Contains(t, string(data), `layout: standalone ? "StandaloneLayout" : "BaseLayout"`)
+
+	resp, err = testClient.Get(testServer.URL + "/api/docs/assets/swagger-ui-standalone-preset.js")
+	require.Nil(t, err)
+	data, err = io.ReadAll(resp.Body)
+	require.Nil(t, err)
+	require.Contains(t, string(data), ".SwaggerUIStandalonePreset=")
+}
+
+func Test_SetUpDocumentsSameSlug(t *testing.T) {
+	server := core.CreateFactory(AppModule)
+	server.SetGlobalPrefix("api")
+
+	require.NotPanics(t, func() {
+		swagger.SetUpDocuments("/docs", server, []swagger.Document{
+			{Name: "Public API", Spec: swagger.NewSpecBuilder().Include(swagger.ByTag("Post"))},
+			{Name: "public api", Spec: swagger.NewSpecBuilder().Include(swagger.ByTag("User"))},
+		})
+	})
+	testServer := httptest.NewServer(server.PrepareBeforeListen())
+	defer testServer.Close()
+
+	for _, path := range []string{"/api/docs/public-api/openapi.json", "/api/docs/public-api-2/openapi.json"} {
+		resp, err := testServer.Client().Get(testServer.URL + path)
+		require.Nil(t, err)
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+	}
+}
+
+func Test_SetUpDocumentsInvalid(t *testing.T) {
+	server := core.CreateFactory(AppModule)
+	server.SetGlobalPrefix("api")
+
+	broken := swagger.NewSpecBuilder()
+	broken.Components.SecuritySchemes["broken"] = nil
+	swagger.SetUpDocuments("/docs", server, []swagger.Document{
+		{Name: "Public", Spec: swagger.NewSpecBuilder()},
+		{Name: "Broken", Spec: broken},
+	})
+	testServer := httptest.NewServer(server.PrepareBeforeListen())
+	defer testServer.Close()
+
+	// The valid document is not served without the UI listing it
+	for _, path := range []string{"/api/docs/public/openapi.json", "/api/docs"} {
+		resp, err := testServer.Client().Get(testServer.URL + path)
+		require.Nil(t, err)
+		require.Equal(t, http.StatusNotFound, resp.StatusCode)
+	}
+}
+
+func Test_DocumentSlug(t *testing.T) {
+	server := core.CreateFactory(AppModule)
+	server.SetGlobalPrefix("api")
+
+	swagger.SetUpDocuments("/docs", server, []swagger.Document{
+		{Name: "Partner API (v2)/beta?", Spec: swagger.NewSpecBuilder()},
+		{Name: "日本", Spec: swagger.NewSpecBuilder()},
+	})
+	testServer := httptest.NewServer(server.PrepareBeforeListen())
+	defer testServer.Close()
+
+	for _, path := range []string{"/api/docs/partner-api-v2beta/openapi.json", "/api/docs/document/openapi.json"} {
+		resp, err := testServer.Client().Get(testServer.URL + path)
+		require.Nil(t, err)
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+	}
+}
+
+func healthController(module core.Module) core.Controller {
+	ctrl := module.NewController("Health").Metadata(swagger.ApiExcludeController()).Registry()
+
+	ctrl.Get("", func(ctx core.Ctx) error {
+		return ctx.JSON(core.Map{"status": "ok"})
+	})
+
+	ctrl.Metadata(swagger.ApiExcludeEndpoint(false)).Get("ready", func(ctx core.Ctx) error {
+		return ctx.JSON(core.Map{"status": "ok"})
+	})
+
+	return ctrl
+}
+
+func webhookController(module core.Module) core.Controller {
+	ctrl := module.NewController("Webhooks")
+
+	ctrl.Post("stripe", func(ctx core.Ctx) error {
+		return ctx.JSON(core.Map{"received": true})
+	})
+
+	ctrl.Metadata(swagger.ApiExcludeEndpoint()).Post("internal", func(ctx core.Ctx) error {
+		return ctx.JSON(core.Map{"received": true})
+	})
+
+	return ctrl
+}
+
+func Test_Exclude(t *testing.T) {
+	appModule := func() core.Module {
+		return core.NewModule(core.NewModuleOptions{
+			Imports:     []core.Modules{PostModule},
+			Controllers: []core.Controllers{healthController, webhookController},
+		})
+	}
+	app := core.CreateFactory(appModule)
+	app.SetGlobalPrefix("api")
+
+	spec := swagger.NewSpecBuilder()
+	spec.ParsePaths(app)
+	require.Equal(t, []string{"/api/health/ready", "/api/posts", "/api/posts/{id}", "/api/webhooks/stripe"}, slices.Sorted(maps.Keys(spec.Paths)))
+
+	spec = swagger.NewSpecBuilder().
+		Filter(func(route *core.Router) bool { return route.Name != "webhooks" }).
+		Filter(func(route *core.Router) bool { return route.Method != "DELETE" }).
+		Include(swagger.ByPathPrefix("/webhooks", "/posts"))
+	spec.ParsePaths(app)
+	require.Equal(t, []string{"/api/posts", "/api/posts/{id}"}, slices.Sorted(maps.Keys(spec.Paths)))
+	require.Nil(t, spec.Paths["/api/posts/{id}"].Delete)
+	require.NotNil(t, spec.Paths["/api/posts/{id}"].Get)
+}
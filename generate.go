@@ -0,0 +1,96 @@
+package swagger
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/tinh-tinh/tinhtinh/v2/core"
+)
+
+// Format is the encoding of a document file.
+type Format string
+
+const (
+	FormatJSON Format = "json"
+	FormatYAML Format = "yaml"
+)
+
+// formatOf returns the format of a file from its extension.
+func formatOf(path string) Format {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		return FormatYAML
+	default:
+		return FormatJSON
+	}
+}
+
+// WriteFile writes the document to path, as served by SetUp, creating its
+// directory if needed. The format is inferred from the file extension when
+// not given. JSON documents are indented, so that they can be committed and
+// diffed.
+func (spec *SpecBuilder) WriteFile(path string, format ...Format) error {
+	f := formatOf(path)
+	if len(format) > 0 && format[0] != "" {
+		f = format[0]
+	}
+
+	data, err := spec.Document()
+	if err != nil {
+		return err
+	}
+	switch f {
+	case FormatYAML:
+		data, err = jsonToYAML(data)
+		if err != nil {
+			return err
+		}
+	default:
+		var indented bytes.Buffer
+		if err := json.Indent(&indented, data, "", "  "); err != nil {
+			return err
+		}
+		indented.WriteByte('\n')
+		data = indented.Bytes()
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0o644)
+}
+
+type GenerateOptions struct {
+	// Prefix is the global prefix of the app, as given to SetGlobalPrefix.
+	Prefix string
+	// Configure applies the rest of the app setup affecting the document,
+	// e.g. EnableVersioning.
+	Configure func(app *core.App)
+	// Format of the file, inferred from its extension when empty.
+	Format Format
+}
+
+// Generate builds the app of module without listening, parses its routes
+// into spec and writes the document to path. It lets CI generate the
+// document without booting the server:
+//
+//	err := swagger.Generate(app.AppModule, spec, "openapi.yaml", swagger.GenerateOptions{Prefix: "api"})
+func Generate(module core.ModuleParam, spec *SpecBuilder, path string, opts ...GenerateOptions) error {
+	var opt GenerateOptions
+	if len(opts) > 0 {
+		opt = opts[0]
+	}
+
+	app := core.CreateFactory(module)
+	if opt.Prefix != "" {
+		app.SetGlobalPrefix(opt.Prefix)
+	}
+	if opt.Configure != nil {
+		opt.Configure(app)
+	}
+
+	spec.ParsePaths(app)
+	return spec.WriteFile(path, opt.Format)
+}
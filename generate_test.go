@@ -0,0 +1,66 @@
+package swagger_test
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/tinh-tinh/swagger/v2"
+	"github.com/tinh-tinh/tinhtinh/v2/core"
+	"gopkg.in/yaml.v3"
+)
+
+func Test_WriteFile(t *testing.T) {
+	app := core.CreateFactory(AppModule)
+	app.SetGlobalPrefix("api")
+
+	spec := swagger.NewSpecBuilder()
+	spec.ParsePaths(app)
+
+	dir := t.TempDir()
+	require.Nil(t, spec.WriteFile(filepath.Join(dir, "openapi.json")))
+	data, err := os.ReadFile(filepath.Join(dir, "openapi.json"))
+	require.Nil(t, err)
+	require.Contains(t, string(data), "\n  \"openapi\": \"3.0.0\",\n")
+	var doc map[string]interface{}
+	require.Nil(t, json.Unmarshal(data, &doc))
+	require.Contains(t, doc["paths"], "/api/users")
+	served, err := spec.Document()
+	require.Nil(t, err)
+	require.JSONEq(t, string(served), string(data))
+
+	require.Nil(t, spec.WriteFile(filepath.Join(dir, "docs", "openapi.yml")))
+	data, err = os.ReadFile(filepath.Join(dir, "docs", "openapi.yml"))
+	require.Nil(t, err)
+	require.Nil(t, yaml.Unmarshal(data, &doc))
+	require.Contains(t, doc["paths"], "/api/users")
+
+	require.Nil(t, spec.WriteFile(filepath.Join(dir, "spec"), swagger.FormatYAML))
+	data, err = os.ReadFile(filepath.Join(dir, "spec"))
+	require.Nil(t, err)
+	require.Contains(t, string(data), "openapi: 3.0.0")
+}
+
+func Test_Generate(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "openapi.yaml")
+	err := swagger.Generate(AppModule, swagger.NewSpecBuilder().SetTitle("Generated"), path, swagger.GenerateOptions{
+		Prefix: "api",
+		Configure: func(app *core.App) {
+			app.EnableVersioning(core.VersionOptions{Type: core.URIVersion})
+		},
+	})
+	require.Nil(t, err)
+
+	data, err := os.ReadFile(path)
+	require.Nil(t, err)
+	var doc struct {
+		Info  map[string]interface{} `yaml:"info"`
+		Paths map[string]interface{} `yaml:"paths"`
+	}
+	require.Nil(t, yaml.Unmarshal(data, &doc))
+	require.Equal(t, "Generated", doc.Info["title"])
+	require.Contains(t, doc.Paths, "/api/users/v1")
+	require.Contains(t, doc.Paths, "/api/posts")
+}
@@ -6,8 +6,10 @@ toolchain go1.24.1
 
 require (
 	github.com/getkin/kin-openapi v0.133.0
+	github.com/pmezard/go-difflib v1.0.0
 	github.com/stretchr/testify v1.11.1
 	github.com/tinh-tinh/tinhtinh/v2 v2.3.4
+	gopkg.in/yaml.v3 v3.0.1
 )
 
 require (
@@ -20,8 +22,6 @@ require (
 	github.com/oasdiff/yaml v0.0.0-20250309154309-f31be36b4037 // indirect
 	github.com/oasdiff/yaml3 v0.0.0-20250309153720-d2182401db90 // indirect
 	github.com/perimeterx/marshmallow v1.1.5 // indirect
-	github.com/pmezard/go-difflib v1.0.0 // indirect
 	github.com/ugorji/go/codec v1.2.11 // indirect
 	github.com/woodsbury/decimal128 v1.4.0 // indirect
-	gopkg.in/yaml.v3 v3.0.1 // indirect
 )
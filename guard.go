@@ -0,0 +1,61 @@
+package swagger
+
+import "github.com/tinh-tinh/tinhtinh/v2/core"
+
+// guardScheme maps a guard to the security scheme it enforces.
+type guardScheme struct {
+	guard  string
+	name   string
+	scopes []string
+}
+
+// MapGuard documents the routes protected by the guard with the given name,
+// as declared with ApiGuard, as requiring the given security scheme and
+// scopes. ParsePaths adds the requirement to guarded routes, over the security
+// declared on their controller or module but not over the one declared on the
+// route itself, along with 401 and 403 responses unless the route is public.
+//
+// Guards are identified by name, so guards built by the same factory can be
+// mapped separately:
+//
+//	spec.MapGuard("admin", "oauth2", "admin").
+//		MapGuard("user", "oauth2", "user")
+//	ctrl.Metadata(swagger.ApiGuard("admin")).Guard(RoleGuard("admin"))
+func (spec *SpecBuilder) MapGuard(guard string, scheme string, scopes ...string) *SpecBuilder {
+	spec.guardSchemes = append(spec.guardSchemes, guardScheme{
+		guard:  guard,
+		name:   scheme,
+		scopes: scopes,
+	})
+	return spec
+}
+
+// guardNames returns the names of the guards protecting a route, declared
+// with ApiGuard on its module, its controller or the route itself.
+func guardNames(route *core.Router) []string {
+	var names []string
+	for _, value := range metadataValues(route.Metadata, GUARD) {
+		if guards, ok := value.([]string); ok {
+			names = append(names, guards...)
+		}
+	}
+	return names
+}
+
+// guardRequirement returns the security requirement enforced by the mapped
+// guards of a route, or nil if none of its guards is mapped.
+func (spec *SpecBuilder) guardRequirement(route *core.Router) SecurityRequirement {
+	var requirement SecurityRequirement
+	for _, guard := range guardNames(route) {
+		for _, mapped := range spec.guardSchemes {
+			if mapped.guard != guard {
+				continue
+			}
+			if requirement == nil {
+				requirement = SecurityRequirement{}
+			}
+			requirement[mapped.name] = append(requirement[mapped.name], mapped.scopes...)
+		}
+	}
+	return requirement
+}
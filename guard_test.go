@@ -0,0 +1,222 @@
+package swagger
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/tinh-tinh/tinhtinh/v2/core"
+)
+
+func authGuard(ctx core.Ctx) bool {
+	return ctx.Headers("Authorization") != ""
+}
+
+func roleGuard(role string) core.Guard {
+	return func(ctx core.Ctx) bool {
+		return ctx.Headers("X-Role") == role
+	}
+}
+
+func Test_GuardNames(t *testing.T) {
+	controller := func(module core.Module) core.Controller {
+		ctrl := module.NewController("Users").Metadata(ApiGuard("auth")).Guard(authGuard).Registry()
+
+		ctrl.Metadata(ApiGuard("admin")).Guard(roleGuard("admin")).Get("", func(ctx core.Ctx) error {
+			return ctx.JSON(core.Map{"data": "ok"})
+		})
+
+		return ctrl
+	}
+
+	appModule := func() core.Module {
+		return ModuleMetadata(core.NewModule(core.NewModuleOptions{
+			Guards:      []core.Guard{authGuard},
+			Controllers: []core.Controllers{controller},
+		}), ApiGuard("module"))
+	}
+
+	app := core.CreateFactory(appModule)
+	assert.Equal(t, []string{"module", "auth", "admin"}, guardNames(app.Module.GetRouters()[0]))
+}
+
+func Test_GlobalSecurity(t *testing.T) {
+	controller := func(module core.Module) core.Controller {
+		ctrl := module.NewController("Orders").Registry()
+
+		ctrl.Get("", func(ctx core.Ctx) error {
+			return ctx.JSON(core.Map{"data": "ok"})
+		})
+
+		ctrl.Metadata(ApiSecurityScopes("oauth2", "orders:write")).Post("", func(ctx core.Ctx) error {
+			return ctx.JSON(core.Map{"data": "ok"})
+		})
+
+		ctrl.Metadata(ApiPublic()).Get("public", func(ctx core.Ctx) error {
+			return ctx.JSON(core.Map{"data": "ok"})
+		})
+
+		ctrl.Metadata(ApiGuard("auth")).Guard(authGuard).Delete("{id}", func(ctx core.Ctx) error {
+			return ctx.JSON(core.Map{"data": "ok"})
+		})
+
+		return ctrl
+	}
+
+	appModule := func() core.Module {
+		return core.NewModule(core.NewModuleOptions{
+			Controllers: []core.Controllers{controller},
+		})
+	}
+
+	spec := NewSpecBuilder().SetGlobalSecurity(Require("bearerAuth"))
+	spec.ParsePaths(core.CreateFactory(appModule))
+
+	assert.Equal(t, []map[string][]string{{"bearerAuth": {}}}, spec.Security)
+	assert.Nil(t, spec.Paths["/orders"].Get.Security)
+	assert.Equal(t, []map[string][]string{{"oauth2": {"orders:write"}}}, spec.Paths["/orders"].Post.Security)
+	assert.Equal(t, []map[string][]string{}, spec.Paths["/orders/public"].Get.Security)
+	assert.Nil(t, spec.Paths["/orders/{id}"].Delete.Security)
+
+	detected := NewSpecBuilder().SetGlobalSecurity(Require("bearerAuth")).SetGuardDetection(true)
+	detected.ParsePaths(core.CreateFactory(appModule))
+
+	assert.Equal(t, []map[string][]string{}, detected.Paths["/orders"].Get.Security)
+	assert.Equal(t, []map[string][]string{{"oauth2": {"orders:write"}}}, detected.Paths["/orders"].Post.Security)
+	assert.Nil(t, detected.Paths["/orders/{id}"].Delete.Security)
+}
+
+func Test_MapGuard(t *testing.T) {
+	controller := func(module core.Module) core.Controller {
+		ctrl := module.NewController("Orders").Metadata(ApiGuard("auth")).Guard(authGuard).Registry()
+
+		ctrl.Get("", func(ctx core.Ctx) error {
+			return ctx.JSON(core.Map{"data": "ok"})
+		})
+
+		ctrl.Metadata(ApiGuard("admin")).Guard(roleGuard("admin")).Delete("{id}", func(ctx core.Ctx) error {
+			return ctx.JSON(core.Map{"data": "ok"})
+		})
+
+		ctrl.Metadata(ApiGuard("user")).Guard(roleGuard("user")).Put("{id}", func(ctx core.Ctx) error {
+			return ctx.JSON(core.Map{"data": "ok"})
+		})
+
+		ctrl.Metadata(ApiSecurity("apiKey")).Post("", func(ctx core.Ctx) error {
+			return ctx.JSON(core.Map{"data": "ok"})
+		})
+
+		return ctrl
+	}
+
+	publicController := func(module core.Module) core.Controller {
+		ctrl := module.NewController("Health").Registry()
+
+		ctrl.Get("", func(ctx core.Ctx) error {
+			return ctx.JSON(core.Map{"data": "ok"})
+		})
+
+		return ctrl
+	}
+
+	appModule := func() core.Module {
+		return core.NewModule(core.NewModuleOptions{
+			Controllers: []core.Controllers{controller, publicController},
+		})
+	}
+
+	spec := NewSpecBuilder().
+		MapGuard("auth", "bearerAuth").
+		MapGuard("admin", "oauth2", "admin").
+		MapGuard("user", "oauth2", "user")
+	spec.ParsePaths(core.CreateFactory(appModule))
+
+	list := spec.Paths["/orders"].Get
+	assert.Equal(t, []map[string][]string{{"bearerAuth": {}}}, list.Security)
+	assert.Equal(t, "Unauthorized", list.Responses["401"].Description)
+	assert.Equal(t, "Forbidden", list.Responses["403"].Description)
+
+	remove := spec.Paths["/orders/{id}"].Delete
+	assert.Equal(t, []map[string][]string{{"bearerAuth": {}, "oauth2": {"admin"}}}, remove.Security)
+
+	// Guards built by the same factory are mapped by name
+	update := spec.Paths["/orders/{id}"].Put
+	assert.Equal(t, []map[string][]string{{"bearerAuth": {}, "oauth2": {"user"}}}, update.Security)
+
+	create := spec.Paths["/orders"].Post
+	assert.Equal(t, []map[string][]string{{"apiKey": {}}}, create.Security)
+	assert.NotNil(t, create.Responses["403"])
+
+	health := spec.Paths["/health"].Get
+	assert.Nil(t, health.Security)
+	assert.Nil(t, health.Responses["401"])
+	assert.Nil(t, health.Responses["403"])
+}
+
+func Test_GuardPrecedence(t *testing.T) {
+	controller := func(module core.Module) core.Controller {
+		ctrl := module.NewController("Reports").Metadata(ApiSecurity("apiKey")).Registry()
+
+		ctrl.Get("", func(ctx core.Ctx) error {
+			return ctx.JSON(core.Map{"data": "ok"})
+		})
+
+		ctrl.Metadata(ApiGuard("admin")).Guard(roleGuard("admin")).Delete("{id}", func(ctx core.Ctx) error {
+			return ctx.JSON(core.Map{"data": "ok"})
+		})
+
+		ctrl.Metadata(ApiGuard("admin"), ApiPublic()).Guard(roleGuard("admin")).Get("public", func(ctx core.Ctx) error {
+			return ctx.JSON(core.Map{"data": "ok"})
+		})
+
+		return ctrl
+	}
+
+	exportController := func(module core.Module) core.Controller {
+		ctrl := module.NewController("Exports").Metadata(ApiGuard("admin")).Guard(roleGuard("admin")).Registry()
+
+		ctrl.Get("", func(ctx core.Ctx) error {
+			return ctx.JSON(core.Map{"data": "ok"})
+		})
+
+		ctrl.Metadata(ApiSecurity("apiKey")).Post("", func(ctx core.Ctx) error {
+			return ctx.JSON(core.Map{"data": "ok"})
+		})
+
+		return ctrl
+	}
+
+	appModule := func() core.Module {
+		return ModuleMetadata(core.NewModule(core.NewModuleOptions{
+			Controllers: []core.Controllers{controller, exportController},
+		}), ApiSecurity("bearerAuth"))
+	}
+
+	spec := NewSpecBuilder().MapGuard("admin", "oauth2", "admin")
+	spec.ParsePaths(core.CreateFactory(appModule))
+
+	// Controller security applies to the routes without guards
+	list := spec.Paths["/reports"].Get
+	assert.Equal(t, []map[string][]string{{"apiKey": {}}}, list.Security)
+	assert.Nil(t, list.Responses["401"])
+
+	// The guard of a route overrides the security of its controller
+	remove := spec.Paths["/reports/{id}"].Delete
+	assert.Equal(t, []map[string][]string{{"oauth2": {"admin"}}}, remove.Security)
+	assert.Equal(t, "Unauthorized", remove.Responses["401"].Description)
+
+	// The guard of a controller overrides the security of its module
+	export := spec.Paths["/exports"].Get
+	assert.Equal(t, []map[string][]string{{"oauth2": {"admin"}}}, export.Security)
+	assert.NotNil(t, export.Responses["403"])
+
+	// The security of a route overrides its guards
+	create := spec.Paths["/exports"].Post
+	assert.Equal(t, []map[string][]string{{"apiKey": {}}}, create.Security)
+	assert.NotNil(t, create.Responses["403"])
+
+	// A public route has no error responses of its guards
+	public := spec.Paths["/reports/public"].Get
+	assert.Equal(t, []map[string][]string{}, public.Security)
+	assert.Nil(t, public.Responses["401"])
+	assert.Nil(t, public.Responses["403"])
+}
@@ -26,12 +26,74 @@ func ApiSecurity(names ...string) *core.Metadata {
 	return core.SetMetadata(SECURITY, names)
 }
 
+// ApiSecurityScopes requires the given security scheme with the given
+// scopes, e.g. ApiSecurityScopes("oauth2", "orders:write").
+func ApiSecurityScopes(name string, scopes ...string) *core.Metadata {
+	return core.SetMetadata(SECURITY, []SecurityRequirement{Require(name, scopes...)})
+}
+
+// ApiSecurityAny accepts any one of the given requirements, each of which
+// may combine several schemes:
+//
+//	swagger.ApiSecurityAny(
+//		swagger.Require("oauth2", "orders:write"),
+//		swagger.Require("bearerAuth").And("apiKey"),
+//	)
+func ApiSecurityAny(requirements ...SecurityRequirement) *core.Metadata {
+	return core.SetMetadata(SECURITY, requirements)
+}
+
+// ApiPublic marks a route as not requiring any security, overriding the
+// security declared on its controller, module or document.
+func ApiPublic() *core.Metadata {
+	return core.SetMetadata(SECURITY, []SecurityRequirement{})
+}
+
+const GUARD = "openapi_guard"
+
+// ApiGuard names the tinhtinh guards registered next to it, for MapGuard to
+// document their security. tinhtinh does not expose the guards of a route,
+// so they must be named where they are registered:
+//
+//	ctrl.Metadata(swagger.ApiGuard("admin")).Guard(RoleGuard("admin"))
+func ApiGuard(names ...string) *core.Metadata {
+	return core.SetMetadata(GUARD, names)
+}
+
 const CONSUMER = "openapi_consumer"
 
 func ApiConsumer(names ...string) *core.Metadata {
 	return core.SetMetadata(CONSUMER, names)
 }
 
+const EXTERNAL_DOCS = "openapi_external_docs"
+
+func ApiExternalDocs(url string, description string) *core.Metadata {
+	return core.SetMetadata(EXTERNAL_DOCS, &ExternalDocsObject{Url: url, Description: description})
+}
+
+const DEPRECATED = "openapi_deprecated"
+
+func ApiDeprecated(deprecated ...bool) *core.Metadata {
+	return core.SetMetadata(DEPRECATED, len(deprecated) == 0 || deprecated[0])
+}
+
+const EXCLUDE = "openapi_exclude"
+
+// ApiExcludeEndpoint hides a route from the documentation. Passing false
+// documents a route of an excluded controller.
+func ApiExcludeEndpoint(exclude ...bool) *core.Metadata {
+	return core.SetMetadata(EXCLUDE, len(exclude) == 0 || exclude[0])
+}
+
+// ApiExcludeController hides all the routes of a controller from the
+// documentation, when registered as controller metadata:
+//
+//	module.NewController("Health").Metadata(swagger.ApiExcludeController()).Registry()
+func ApiExcludeController() *core.Metadata {
+	return core.SetMetadata(EXCLUDE, true)
+}
+
 const FILE = "openapi_file"
 
 type FileOptions struct {
@@ -43,3 +105,41 @@ type FileOptions struct {
 func ApiFile(opts ...FileOptions) *core.Metadata {
 	return core.SetMetadata(FILE, opts)
 }
+
+const BODY_EXAMPLE = "openapi_body_example"
+
+// ApiBodyExample adds a named example of the request body. The value is
+// encoded with the app encoder, so it can be any Go value the body parser
+// would accept.
+func ApiBodyExample(name string, value interface{}) *core.Metadata {
+	return core.SetMetadata(BODY_EXAMPLE, namedExample{Name: name, Value: value})
+}
+
+const MODULE = "openapi_module"
+
+// ApiModule names the module of a route, for ByModule to select it. It is
+// meant to be applied to all the routes of a module with ModuleMetadata:
+//
+//	swagger.ModuleMetadata(module, swagger.ApiModule("partner"))
+func ApiModule(name string) *core.Metadata {
+	return core.SetMetadata(MODULE, name)
+}
+
+// ModuleMetadata applies the given metadata to every route of the module, with
+// a lower precedence than metadata declared on its controllers and routes.
+//
+// It is meant to wrap the module returned by a module function:
+//
+//	func UserModule(module core.Module) core.Module {
+//		return swagger.ModuleMetadata(module.New(core.NewModuleOptions{
+//			Controllers: []core.Controllers{userController},
+//		}), swagger.ApiTag("Users"), swagger.ApiSecurity("bearerAuth"))
+//	}
+func ModuleMetadata(module core.Module, metadata ...*core.Metadata) core.Module {
+	for _, router := range module.GetRouters() {
+		merged := make([]*core.Metadata, 0, len(metadata)+len(router.Metadata))
+		merged = append(merged, metadata...)
+		router.Metadata = append(merged, router.Metadata...)
+	}
+	return module
+}
@@ -1,8 +1,11 @@
 package swagger_test
 
 import (
+	"html"
+	"io"
 	"net/http"
 	"net/http/httptest"
+	"regexp"
 	"testing"
 
 	"github.com/stretchr/testify/require"
@@ -53,3 +56,235 @@ func Test_Persistent(t *testing.T) {
 	require.Nil(t, err)
 	require.Equal(t, http.StatusOK, resp.StatusCode)
 }
+
+func Test_DocumentPath(t *testing.T) {
+	server := core.CreateFactory(AppModule)
+	server.SetGlobalPrefix("api")
+
+	document := swagger.NewSpecBuilder()
+	swagger.SetUp("/docs", server, document, swagger.Config{
+		DocumentPath: "/internal/openapi.json",
+		DocumentURL:  "/gateway/internal/openapi.json",
+	})
+	testServer := httptest.NewServer(server.PrepareBeforeListen())
+	defer testServer.Close()
+
+	testClient := testServer.Client()
+	resp, err := testClient.Get(testServer.URL + "/internal/openapi.json")
+	require.Nil(t, err)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+
+	resp, err = testClient.Get(testServer.URL + "/internal/openapi.yaml")
+	require.Nil(t, err)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+
+	resp, err = testClient.Get(testServer.URL + "/api/docs")
+	require.Nil(t, err)
+	data, err := io.ReadAll(resp.Body)
+	require.Nil(t, err)
+	require.Contains(t, html.UnescapeString(string(data)), `"url":"/gateway/internal/openapi.json"`)
+}
+
+func Test_Assets(t *testing.T) {
+	server := core.CreateFactory(AppModule)
+	server.SetGlobalPrefix("api")
+
+	swagger.SetUp("/swagger", server, swagger.NewSpecBuilder())
+	testServer := httptest.NewServer(server.PrepareBeforeListen())
+	defer testServer.Close()
+
+	testClient := testServer.Client()
+	resp, err := testClient.Get(testServer.URL + "/api/swagger")
+	require.Nil(t, err)
+	data, err := io.ReadAll(resp.Body)
+	require.Nil(t, err)
+	require.Contains(t, string(data), `src="/api/swagger/assets/swagger-ui-bundle.js"`)
+	require.NotContains(t, string(data), "https://")
+
+	resp, err = testClient.Get(testServer.URL + "/api/swagger/assets/swagger-ui-bundle.js")
+	require.Nil(t, err)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+
+	resp, err = testClient.Get(testServer.URL + "/api/swagger/assets/swagger-ui.css")
+	require.Nil(t, err)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	require.Contains(t, resp.Header.Get("Content-Type"), "text/css")
+
+	resp, err = testClient.Get(testServer.URL + "/api/swagger/assets/LICENSE")
+	require.Nil(t, err)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+}
+
+func Test_CDNAssets(t *testing.T) {
+	server := core.CreateFactory(AppModule)
+	server.SetGlobalPrefix("api")
+
+	swagger.SetUp("/swagger", server, swagger.NewSpecBuilder(), swagger.Config{
+		Assets:       swagger.CDNAssets,
+		AssetVersion: "5.17.14",
+	})
+	testServer := httptest.NewServer(server.PrepareBeforeListen())
+	defer testServer.Close()
+
+	testClient := testServer.Client()
+	resp, err := testClient.Get(testServer.URL + "/api/swagger")
+	require.Nil(t, err)
+	data, err := io.ReadAll(resp.Body)
+	require.Nil(t, err)
+	require.Contains(t, string(data), "https://cdn.jsdelivr.net/npm/swagger-ui-dist@5.17.14/swagger-ui-bundle.js")
+
+	resp, err = testClient.Get(testServer.URL + "/api/swagger/assets/swagger-ui-bundle.js")
+	require.Nil(t, err)
+	require.Equal(t, http.StatusNotFound, resp.StatusCode)
+}
+
+func Test_MountUI(t *testing.T) {
+	server := core.CreateFactory(AppModule)
+	server.SetGlobalPrefix("api")
+
+	swagger.SetUp("/swagger", server, swagger.NewSpecBuilder(), swagger.Config{
+		PersistAuthorization: true,
+	})
+	swagger.MountUI("/redoc", server, "/api/swagger/openapi.json", swagger.Config{
+		UI:        swagger.ReDoc,
+		UIOptions: map[string]any{"hideDownloadButton": true},
+	})
+	swagger.MountUI("/scalar", server, "/api/swagger/openapi.json", swagger.Config{UI: swagger.Scalar})
+	swagger.MountUI("/rapidoc", server, "/api/swagger/openapi.json", swagger.Config{
+		UI:        swagger.RapiDoc,
+		UIOptions: map[string]any{"theme": "dark"},
+	})
+	swagger.MountUI("/elements", server, "/api/swagger/openapi.json", swagger.Config{UI: swagger.StoplightElements})
+	testServer := httptest.NewServer(server.PrepareBeforeListen())
+	defer testServer.Close()
+
+	testCases := []struct {
+		path     string
+		contains []string
+	}{
+		{"/api/swagger", []string{`"persistAuthorization":true`, `"url":"/api/swagger/openapi.json"`}},
+		{"/api/redoc", []string{"redoc@2.1.5/bundles/redoc.standalone.js", `data-url="/api/swagger/openapi.json"`, `data-config="{"hideDownloadButton":true}"`}},
+		{"/api/scalar", []string{`data-url="/api/swagger/openapi.json"`, "@scalar/api-reference@1.24.0"}},
+		{"/api/rapidoc", []string{`"spec-url":"/api/swagger/openapi.json"`, `"theme":"dark"`}},
+		{"/api/elements", []string{`"apiDescriptionUrl":"/api/swagger/openapi.json"`, `"router":"hash"`}},
+	}
+	testClient := testServer.Client()
+	for _, tc := range testCases {
+		resp, err := testClient.Get(testServer.URL + tc.path)
+		require.Nil(t, err)
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		data, err := io.ReadAll(resp.Body)
+		require.Nil(t, err)
+		for _, s := range tc.contains {
+			require.Contains(t, html.UnescapeString(string(data)), s)
+		}
+	}
+}
+
+func Test_UnknownUI(t *testing.T) {
+	server := core.CreateFactory(AppModule)
+	server.SetGlobalPrefix("api")
+
+	require.PanicsWithValue(t,
+		`swagger: unknown UI "swagger", expected one of rapidoc, redoc, scalar, stoplight-elements, swagger-ui`,
+		func() {
+			swagger.SetUp("/swagger", server, swagger.NewSpecBuilder(), swagger.Config{UI: "swagger"})
+		})
+}
+
+func Test_SwaggerUIConfig(t *testing.T) {
+	server := core.CreateFactory(AppModule)
+	server.SetGlobalPrefix("api")
+
+	depth := -1
+	swagger.SetUp("/swagger", server, swagger.NewSpecBuilder(), swagger.Config{
+		Title:                    "Orders </title>",
+		DocExpansion:             "none",
+		Filter:                   true,
+		TryItOutEnabled:          true,
+		DisplayRequestDuration:   true,
+		DefaultModelsExpandDepth: &depth,
+		SyntaxHighlight:          &swagger.SyntaxHighlightConfig{Activated: true, Theme: "monokai"},
+		SupportedSubmitMethods:   []string{"get"},
+		ValidatorUrl:             "none",
+		CustomCSS:                ".topbar { display: none }",
+		InitOAuth: &swagger.OAuthConfig{
+			ClientId:                          "docs",
+			Scopes:                            []string{"orders:read"},
+			UsePkceWithAuthorizationCodeGrant: true,
+		},
+		RequestInterceptor: `(req) => { req.headers["X-Docs"] = "1"; return req; }`,
+	})
+	testServer := httptest.NewServer(server.PrepareBeforeListen())
+	defer testServer.Close()
+
+	resp, err := testServer.Client().Get(testServer.URL + "/api/swagger")
+	require.Nil(t, err)
+	require.Equal(t, "text/html; charset=utf-8", resp.Header.Get("Content-Type"))
+	data, err := io.ReadAll(resp.Body)
+	require.Nil(t, err)
+	require.Contains(t, string(data), "<title>Orders &lt;/title&gt;</title>")
+	page := html.UnescapeString(string(data))
+	require.Contains(t, page, `"docExpansion":"none"`)
+	require.Contains(t, page, `"filter":true`)
+	require.Contains(t, page, `"tryItOutEnabled":true`)
+	require.Contains(t, page, `"displayRequestDuration":true`)
+	require.Contains(t, page, `"defaultModelsExpandDepth":-1`)
+	require.Contains(t, page, `"syntaxHighlight":{"activated":true,"theme":"monokai"}`)
+	require.Contains(t, page, `"supportedSubmitMethods":["get"]`)
+	require.Contains(t, page, `"validatorUrl":"none"`)
+	require.Contains(t, page, `"oauth2RedirectUrl":"/api/swagger/assets/oauth2-redirect.html"`)
+	require.Contains(t, page, `<style>.topbar { display: none }</style>`)
+	require.Contains(t, page, `data-oauth="{"clientId":"docs","scopes":["orders:read"],"usePkceWithAuthorizationCodeGrant":true}"`)
+
+	resp, err = testServer.Client().Get(testServer.URL + "/api/swagger/init.js")
+	require.Nil(t, err)
+	data, err = io.ReadAll(resp.Body)
+	require.Nil(t, err)
+	require.Contains(t, string(data), `requestInterceptor: (req) => { req.headers["X-Docs"] = "1"; return req; },`)
+}
+
+func Test_CSPNonce(t *testing.T) {
+	server := core.CreateFactory(AppModule)
+	server.SetGlobalPrefix("api")
+
+	swagger.SetUp("/swagger", server, swagger.NewSpecBuilder(), swagger.Config{
+		CSPNonce:  true,
+		CustomCSS: ".topbar { display: none }",
+	})
+	swagger.MountUI("/redoc", server, "/api/swagger/openapi.json", swagger.Config{
+		UI:                    swagger.ReDoc,
+		ContentSecurityPolicy: "script-src 'nonce-{nonce}' https://cdn.jsdelivr.net",
+		CSPNonce:              true,
+	})
+	testServer := httptest.NewServer(server.PrepareBeforeListen())
+	defer testServer.Close()
+
+	testClient := testServer.Client()
+	nonces := map[string]bool{}
+	for i := 0; i < 2; i++ {
+		resp, err := testClient.Get(testServer.URL + "/api/swagger")
+		require.Nil(t, err)
+		data, err := io.ReadAll(resp.Body)
+		require.Nil(t, err)
+
+		policy := resp.Header.Get("Content-Security-Policy")
+		require.Contains(t, policy, "default-src 'self'")
+		nonce := regexp.MustCompile(`'nonce-([^']+)'`).FindStringSubmatch(policy)
+		require.Len(t, nonce, 2)
+		nonces[nonce[1]] = true
+		require.NotContains(t, string(data), "<script>")
+		require.Contains(t, string(data), `<script src="/api/swagger/init.js" nonce="`+nonce[1]+`"></script>`)
+		require.Contains(t, string(data), `<style nonce="`+nonce[1]+`">`)
+	}
+	require.Len(t, nonces, 2)
+
+	resp, err := testClient.Get(testServer.URL + "/api/redoc")
+	require.Nil(t, err)
+	require.Regexp(t, `^script-src 'nonce-[^']+' https://cdn.jsdelivr.net$`, resp.Header.Get("Content-Security-Policy"))
+
+	resp, err = testClient.Get(testServer.URL + "/api/redoc/init.js")
+	require.Nil(t, err)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	require.Equal(t, "text/javascript; charset=utf-8", resp.Header.Get("Content-Type"))
+}
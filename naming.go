@@ -0,0 +1,203 @@
+package swagger
+
+import (
+	"fmt"
+	"path"
+	"reflect"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// SchemaNamingStrategy returns the component name of a struct type. It is
+// applied to all the types sharing a struct name, for example when two
+// modules both declare a CreateDto.
+type SchemaNamingStrategy func(t reflect.Type) string
+
+// PackageQualifiedNaming names a schema after its package and struct name,
+// e.g. users.CreateDto.
+func PackageQualifiedNaming(t reflect.Type) string {
+	return sanitizeSchemaName(packageName(t.PkgPath()) + "." + t.Name())
+}
+
+// packageName returns the last element of an import path which is not a
+// major version suffix.
+func packageName(pkgPath string) string {
+	pkg := path.Base(pkgPath)
+	if isMajorVersion(pkg) {
+		pkg = path.Base(path.Dir(pkgPath))
+	}
+	return pkg
+}
+
+// ModulePrefixedNaming names a schema after its full import path and struct
+// name, e.g. github.com.acme.app.users.CreateDto.
+func ModulePrefixedNaming(t reflect.Type) string {
+	return sanitizeSchemaName(strings.ReplaceAll(t.PkgPath(), "/", ".") + "." + t.Name())
+}
+
+// schemaRegistry keeps track of the struct types referenced by a document.
+// Component names are resolved once every type is known, so that distinct
+// types sharing the same struct name are all renamed, whatever the order of
+// the routes declaring them.
+type schemaRegistry struct {
+	types []reflect.Type
+	index map[reflect.Type]int
+	// routes lists the route which referenced each type first.
+	routes []string
+	// refs lists the schemas returned by refSchema, whose references are
+	// placeholders until resolve.
+	refs        []*SchemaObject
+	naming      SchemaNamingStrategy
+	route       string
+	diagnostics []Diagnostic
+}
+
+func newSchemaRegistry(naming SchemaNamingStrategy) *schemaRegistry {
+	if naming == nil {
+		naming = PackageQualifiedNaming
+	}
+	return &schemaRegistry{
+		index:  make(map[reflect.Type]int),
+		naming: naming,
+	}
+}
+
+// register adds the struct type t and returns the placeholder $ref of its
+// component, replaced by resolve.
+func (r *schemaRegistry) register(t reflect.Type) string {
+	i, ok := r.index[t]
+	if !ok {
+		i = len(r.types)
+		r.index[t] = i
+		r.types = append(r.types, t)
+		r.routes = append(r.routes, r.route)
+	}
+	return pendingRef(i)
+}
+
+func pendingRef(i int) string {
+	return "#/components/schemas/\x00" + strconv.Itoa(i)
+}
+
+// resolve names the components, replaces the placeholders of the registered
+// schemas with their references and returns the component schemas.
+//
+// Types named alone keep their struct name. Types sharing one are all named
+// with the naming strategy, in the order of their package paths, numbered
+// when the strategy still gives the same name.
+func (r *schemaRegistry) resolve() map[string]*SchemaObject {
+	groups := make(map[string][]int)
+	for i, t := range r.types {
+		name := sanitizeSchemaName(t.Name())
+		groups[name] = append(groups[name], i)
+	}
+
+	names := make([]string, len(r.types))
+	taken := make(map[string]bool, len(r.types))
+	var collisions []string
+	for name, group := range groups {
+		if len(group) == 1 {
+			names[group[0]] = name
+			taken[name] = true
+		} else {
+			collisions = append(collisions, name)
+		}
+	}
+	slices.Sort(collisions)
+
+	for _, name := range collisions {
+		group := groups[name]
+		route := r.routes[group[1]]
+		slices.SortStableFunc(group, func(a, b int) int {
+			return strings.Compare(r.types[a].PkgPath(), r.types[b].PkgPath())
+		})
+
+		renamed := make([]string, 0, len(group))
+		for _, i := range group {
+			qualified := sanitizeSchemaName(r.naming(r.types[i]))
+			resolved := qualified
+			for n := 2; taken[resolved]; n++ {
+				resolved = fmt.Sprintf("%s_%d", qualified, n)
+			}
+			taken[resolved] = true
+			names[i] = resolved
+			renamed = append(renamed, fmt.Sprintf("%s as %q", r.types[i], resolved))
+		}
+		r.diagnostics = append(r.diagnostics, Diagnostic{
+			Kind:    DiagnosticSchemaCollision,
+			Route:   route,
+			Message: fmt.Sprintf("schema %q is declared by several types, named %s", name, strings.Join(renamed, ", ")),
+		})
+	}
+
+	refs := make(map[string]string, len(names))
+	schemas := make(map[string]*SchemaObject, len(names))
+	for i, t := range r.types {
+		refs[pendingRef(i)] = "#/components/schemas/" + names[i]
+		schemas[names[i]] = ParseSchema(reflect.New(t).Interface())
+	}
+	for _, schema := range r.refs {
+		resolveRefs(schema, refs)
+	}
+	return schemas
+}
+
+// resolveRefs replaces the placeholder references of a schema built by
+// typeSchema, which are nested in array items and map values.
+func resolveRefs(schema *SchemaObject, refs map[string]string) {
+	if schema == nil {
+		return
+	}
+	if ref, ok := refs[schema.Ref]; ok {
+		schema.Ref = ref
+	}
+	resolveItemsRefs(schema.Items, refs)
+	resolveRefs(schema.AdditionalProperties, refs)
+}
+
+func resolveItemsRefs(items *ItemsObject, refs map[string]string) {
+	if items == nil {
+		return
+	}
+	if ref, ok := refs[items.Ref]; ok {
+		items.Ref = ref
+	}
+	resolveItemsRefs(items.Items, refs)
+	resolveRefs(items.AdditionalProperties, refs)
+}
+
+func isMajorVersion(elem string) bool {
+	if len(elem) < 2 || elem[0] != 'v' {
+		return false
+	}
+	return strings.Trim(elem[1:], "0123456789") == ""
+}
+
+// typeArg matches the qualified type arguments in the name of a generic
+// type, e.g. "github.com/acme/app/users.User" in
+// "Page[github.com/acme/app/users.User]".
+var typeArg = regexp.MustCompile(`([^\[\],]*/[^\[\],/]*)\.([^\[\],./]*)`)
+
+// sanitizeSchemaName replaces characters not allowed in component names,
+// which may only contain A-Z, a-z, 0-9, ".", "-" and "_". Type arguments of
+// generic types keep their package name, e.g. Page[users.User] is named
+// Page_users.User.
+func sanitizeSchemaName(name string) string {
+	name = typeArg.ReplaceAllStringFunc(name, func(arg string) string {
+		match := typeArg.FindStringSubmatch(arg)
+		return packageName(match[1]) + "." + match[2]
+	})
+	name = strings.NewReplacer("[", "_", ",", "_", "]", "").Replace(name)
+	return strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9':
+			return r
+		case r == '.', r == '-', r == '_':
+			return r
+		default:
+			return '_'
+		}
+	}, name)
+}
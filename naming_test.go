@@ -0,0 +1,155 @@
+package swagger
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tinh-tinh/tinhtinh/v2/core"
+)
+
+type Info struct {
+	Title string `json:"title"`
+}
+
+type Page[T any] struct {
+	Items []T `json:"items"`
+}
+
+func Test_SchemaCollision(t *testing.T) {
+	local := reflect.TypeOf(Info{})
+	kin := reflect.TypeOf(openapi3.Info{})
+
+	// Colliding types are all qualified, in the same way whatever the order
+	// they are registered in
+	for _, types := range [][]reflect.Type{{local, kin}, {kin, local}} {
+		registry := newSchemaRegistry(nil)
+		refs := []*SchemaObject{}
+		for _, typ := range types {
+			registry.route = "POST /" + typ.PkgPath()
+			ref := &SchemaObject{Ref: registry.register(typ)}
+			assert.Equal(t, ref.Ref, registry.register(typ))
+			registry.refs = append(registry.refs, ref)
+			refs = append(refs, ref)
+		}
+
+		schemas := registry.resolve()
+		assert.Len(t, schemas, 2)
+		assert.NotNil(t, schemas["swagger.Info"].Properties["title"])
+		assert.NotNil(t, schemas["openapi3.Info"])
+		assert.Equal(t, "#/components/schemas/"+PackageQualifiedNaming(types[0]), refs[0].Ref)
+		assert.Equal(t, "#/components/schemas/"+PackageQualifiedNaming(types[1]), refs[1].Ref)
+
+		require.Len(t, registry.diagnostics, 1)
+		assert.Equal(t, DiagnosticSchemaCollision, registry.diagnostics[0].Kind)
+		assert.Equal(t, "POST /"+types[1].PkgPath(), registry.diagnostics[0].Route)
+		assert.Equal(t, `schema "Info" is declared by several types, named openapi3.Info as "openapi3.Info", swagger.Info as "swagger.Info"`, registry.diagnostics[0].Message)
+	}
+
+	// Types of the same package are numbered
+	userDto := func() reflect.Type {
+		type CreateDto struct {
+			Name string `json:"name"`
+		}
+		return reflect.TypeOf(CreateDto{})
+	}()
+	postDto := func() reflect.Type {
+		type CreateDto struct {
+			Title string `json:"title"`
+		}
+		return reflect.TypeOf(CreateDto{})
+	}()
+	registry := newSchemaRegistry(nil)
+	registry.register(userDto)
+	registry.register(postDto)
+	schemas := registry.resolve()
+	assert.NotNil(t, schemas["swagger.CreateDto"].Properties["name"])
+	assert.NotNil(t, schemas["swagger.CreateDto_2"].Properties["title"])
+
+	custom := newSchemaRegistry(func(t reflect.Type) string { return "Post" + t.Name() })
+	custom.register(userDto)
+	custom.register(postDto)
+	assert.Contains(t, custom.resolve(), "PostCreateDto_2")
+}
+
+func Test_GenericSchemaName(t *testing.T) {
+	registry := newSchemaRegistry(nil)
+	ref := &SchemaObject{Type: "array", Items: &ItemsObject{Ref: registry.register(reflect.TypeOf(Page[InfoObject]{}))}}
+	registry.refs = append(registry.refs, ref)
+
+	schemas := registry.resolve()
+	assert.Contains(t, schemas, "Page_swagger.InfoObject")
+	assert.Equal(t, "#/components/schemas/Page_swagger.InfoObject", ref.Items.Ref)
+
+	assert.Equal(t, "swagger.Page_openapi3.Info", PackageQualifiedNaming(reflect.TypeOf(Page[openapi3.Info]{})))
+	assert.Equal(t, "Pair_yaml.v3.Node_swagger.Info", sanitizeSchemaName("Pair[gopkg.in/yaml.v3.Node,github.com/tinh-tinh/swagger/v2.Info]"))
+}
+
+// nilBody is a body pipe without value.
+type nilBody struct{}
+
+func (nilBody) GetValue() interface{} { return nil }
+
+func (nilBody) GetLocation() core.CtxKey { return core.InBody }
+
+func Test_ParsePathsSchemaCollision(t *testing.T) {
+	type CreateDto struct {
+		Name string `json:"name"`
+	}
+	userController := func(module core.Module) core.Controller {
+		ctrl := module.NewController("Users").Registry()
+		ctrl.Pipe(core.BodyParser[CreateDto]{}).Post("", func(ctx core.Ctx) error {
+			return ctx.JSON(core.Map{"data": "ok"})
+		})
+		ctrl.Pipe(core.BodyParser[[]string]{}).Put("", func(ctx core.Ctx) error {
+			return ctx.JSON(core.Map{"data": "ok"})
+		})
+		ctrl.Pipe(nilBody{}).Patch("", func(ctx core.Ctx) error {
+			return ctx.JSON(core.Map{"data": "ok"})
+		})
+		return ctrl
+	}
+	postDto := func() core.PipeDto {
+		type CreateDto struct {
+			Title string `json:"title"`
+		}
+		return core.BodyParser[CreateDto]{}
+	}()
+	postController := func(module core.Module) core.Controller {
+		ctrl := module.NewController("Posts").Registry()
+		ctrl.Pipe(postDto).Post("", func(ctx core.Ctx) error {
+			return ctx.JSON(core.Map{"data": "ok"})
+		})
+		return ctrl
+	}
+	appModule := func() core.Module {
+		return core.NewModule(core.NewModuleOptions{
+			Controllers: []core.Controllers{userController, postController},
+		})
+	}
+
+	spec := NewSpecBuilder()
+	spec.ParsePaths(core.CreateFactory(appModule))
+
+	users := spec.Paths["/users"]
+	assert.Equal(t, "#/components/schemas/swagger.CreateDto", users.Post.RequestBody.Content["application/json"].Schema.Ref)
+	assert.Equal(t, "#/components/schemas/swagger.CreateDto_2", spec.Paths["/posts"].Post.RequestBody.Content["application/json"].Schema.Ref)
+	assert.NotNil(t, spec.Components.Schemas["swagger.CreateDto"].Properties["name"])
+	assert.NotNil(t, spec.Components.Schemas["swagger.CreateDto_2"].Properties["title"])
+
+	require.Len(t, spec.Diagnostics, 1)
+	assert.Equal(t, DiagnosticSchemaCollision, spec.Diagnostics[0].Kind)
+	assert.Equal(t, "POST /posts", spec.Diagnostics[0].Route)
+
+	// Slice bodies are described inline, and bodies without value skipped
+	assert.Equal(t, "array", users.Put.RequestBody.Content["application/json"].Schema.Type)
+	assert.Nil(t, users.Patch.RequestBody)
+}
+
+func Test_SchemaNaming(t *testing.T) {
+	typ := reflect.TypeOf(InfoObject{})
+	assert.Equal(t, "swagger.InfoObject", PackageQualifiedNaming(typ))
+	assert.Equal(t, "github.com.tinh-tinh.swagger.v2.InfoObject", ModulePrefixedNaming(typ))
+}
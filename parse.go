@@ -1,12 +1,14 @@
 package swagger
 
 import (
+	"encoding/json"
+	"fmt"
 	"reflect"
 	"slices"
+	"strconv"
 	"strings"
 	"time"
 
-	"github.com/tinh-tinh/tinhtinh/v2/common"
 	"github.com/tinh-tinh/tinhtinh/v2/core"
 )
 
@@ -27,20 +29,43 @@ import (
 //     dto in the definitions section.
 //   - If the dto is in the query or path, it will be replaced with the name
 //     of the dto in the parameters section.
+//
+// Metadata can be declared on a module with ModuleMetadata, on a controller
+// before calling Registry, or on a single route. When the same metadata is
+// declared at several levels the most specific one wins: route metadata
+// overrides controller metadata, which overrides module metadata. Examples
+// are the exception, they are collected from every level.
 func (spec *SpecBuilder) ParsePaths(app *core.App) {
 	// mapperDoc := app.Module.MapperDoc
 	routes := app.Module.GetRouters()
 
 	pathObject := make(PathObject)
-	schemas := make(map[string]*SchemaObject)
+	registry := newSchemaRegistry(spec.schemaNaming)
+	encoder := spec.encoderOf(app)
+	versioning, err := spec.versioningOf(app)
+	if err != nil {
+		// core.App keeps its versioning unexported, a change of tinhtinh
+		// must not prevent the document from being generated
+		registry.diagnostics = append(registry.diagnostics, Diagnostic{
+			Kind:    DiagnosticVersioning,
+			Message: err.Error() + ", routes are documented without versioning, set it with SetVersioning",
+		})
+	}
 
 	// Parse routes
-	for _, route := range routes {
+	for i, route := range routes {
+		if !spec.documents(route) {
+			continue
+		}
 		parseRoute := core.ParseRoute(route.Method + " " + route.Path)
+		if versioning != nil && versioning.Type == core.URIVersion && route.Version != "" {
+			parseRoute.SetPrefix("v" + route.Version)
+		}
 		parseRoute.SetPrefix(route.Name)
 		if app.Prefix != "" {
 			parseRoute.SetPrefix(app.Prefix)
 		}
+		registry.route = parseRoute.GetPath()
 		parameters := []*ParameterObject{}
 		mediaTypes := make(map[string]*MediaTypeObject)
 		dtos := route.Dtos
@@ -49,11 +74,8 @@ func (spec *SpecBuilder) ParsePaths(app *core.App) {
 			val := dto.GetValue()
 			switch dto.GetLocation() {
 			case core.InBody:
-				schemas[common.GetStructName(val)] = ParseSchema(val)
-				mediaTypes[common.GetStructName(val)] = &MediaTypeObject{
-					Schema: &SchemaObject{
-						Ref: "#/components/schemas/" + common.GetStructName(val),
-					},
+				if schema := refSchema(val, registry); schema != nil {
+					mediaTypes["application/json"] = &MediaTypeObject{Schema: schema}
 				}
 			case core.InQuery:
 				parameters = append(parameters, ScanQuery(val, dto.GetLocation())...)
@@ -62,9 +84,7 @@ func (spec *SpecBuilder) ParsePaths(app *core.App) {
 			}
 		}
 
-		fileIdx := slices.IndexFunc(route.Metadata, func(v *core.Metadata) bool {
-			return v.Key == FILE
-		})
+		fileIdx := findMetadata(route.Metadata, FILE)
 		if fileIdx != -1 {
 			files, ok := route.Metadata[fileIdx].Value.([]FileOptions)
 			if ok {
@@ -91,21 +111,34 @@ func (spec *SpecBuilder) ParsePaths(app *core.App) {
 			Description: "Ok",
 		}
 
-		findOkIdx := slices.IndexFunc(route.Metadata, func(v *core.Metadata) bool {
-			return v.Key == OK_RESPONSE
-		})
+		findOkIdx := findMetadata(route.Metadata, OK_RESPONSE)
 
 		if findOkIdx != -1 {
 			res := route.Metadata[findOkIdx].Value
-			schemas[common.GetStructName(res)] = ParseSchema(res)
+			if schema := refSchema(res, registry); schema != nil {
+				response.Content = map[string]*ContentObject{
+					"application/json": {Schema: schema},
+				}
+			}
+		}
 
-			content := &ContentObject{
-				Schema: &SchemaObject{
-					Ref: "#/components/schemas/" + common.GetStructName(res),
-				},
+		// Api Ok Response Example
+		if examples := encodeExamples(route.Metadata, OK_RESPONSE_EXAMPLE, encoder, registry); len(examples) > 0 {
+			if response.Content == nil {
+				response.Content = map[string]*ContentObject{"application/json": {}}
 			}
-			response.Content = map[string]*ContentObject{
-				"application/json": content,
+			for _, content := range response.Content {
+				content.Examples = examples
+			}
+		}
+
+		// Api Body Example
+		if examples := encodeExamples(route.Metadata, BODY_EXAMPLE, encoder, registry); len(examples) > 0 {
+			if len(mediaTypes) == 0 {
+				mediaTypes["application/json"] = &MediaTypeObject{}
+			}
+			for _, mediaType := range mediaTypes {
+				mediaType.Examples = examples
 			}
 		}
 
@@ -115,7 +148,6 @@ func (spec *SpecBuilder) ParsePaths(app *core.App) {
 			Consumes:   []string{},
 			Parameters: parameters,
 			Responses:  res,
-			Security:   []map[string][]string{},
 		}
 
 		if len(mediaTypes) > 0 {
@@ -126,7 +158,7 @@ func (spec *SpecBuilder) ParsePaths(app *core.App) {
 		}
 
 		// Api Tag
-		tagIndex := slices.IndexFunc(route.Metadata, func(v *core.Metadata) bool { return v.Key == TAG })
+		tagIndex := findMetadata(route.Metadata, TAG)
 		if tagIndex != -1 {
 			tags, ok := route.Metadata[tagIndex].Value.([]string)
 			if ok {
@@ -135,7 +167,7 @@ func (spec *SpecBuilder) ParsePaths(app *core.App) {
 		}
 
 		// Api Description
-		descriptionIndex := slices.IndexFunc(route.Metadata, func(v *core.Metadata) bool { return v.Key == DESCRIPTION })
+		descriptionIndex := findMetadata(route.Metadata, DESCRIPTION)
 		if descriptionIndex != -1 {
 			description, ok := route.Metadata[descriptionIndex].Value.(string)
 			if ok {
@@ -144,7 +176,7 @@ func (spec *SpecBuilder) ParsePaths(app *core.App) {
 		}
 
 		// Api Summary
-		summaryIndex := slices.IndexFunc(route.Metadata, func(v *core.Metadata) bool { return v.Key == SUMMARY })
+		summaryIndex := findMetadata(route.Metadata, SUMMARY)
 		if summaryIndex != -1 {
 			summary, ok := route.Metadata[summaryIndex].Value.(string)
 			if ok {
@@ -153,20 +185,19 @@ func (spec *SpecBuilder) ParsePaths(app *core.App) {
 		}
 
 		// Api Security
-		secureIndex := slices.IndexFunc(route.Metadata, func(v *core.Metadata) bool { return v.Key == SECURITY })
-		if secureIndex != -1 {
-			securities, ok := route.Metadata[secureIndex].Value.([]string)
-			if ok {
-				security := map[string][]string{}
-				for _, s := range securities {
-					security[s] = []string{}
-				}
-				operation.Security = append(operation.Security, security)
+		guardRequirement := spec.guardRequirement(route)
+		operation.Security = spec.routeSecurity(route, ownMetadata(routes, i), guardRequirement)
+		if guardRequirement != nil && len(operation.Security) > 0 {
+			if operation.Responses["401"] == nil {
+				operation.Responses["401"] = &ResponseObject{Description: "Unauthorized"}
+			}
+			if operation.Responses["403"] == nil {
+				operation.Responses["403"] = &ResponseObject{Description: "Forbidden"}
 			}
 		}
 
 		// Api Consumer
-		consumerIndex := slices.IndexFunc(route.Metadata, func(v *core.Metadata) bool { return v.Key == CONSUMER })
+		consumerIndex := findMetadata(route.Metadata, CONSUMER)
 		if consumerIndex != -1 {
 			consumers, ok := route.Metadata[consumerIndex].Value.([]string)
 			if ok {
@@ -174,28 +205,176 @@ func (spec *SpecBuilder) ParsePaths(app *core.App) {
 			}
 		}
 
+		// Api External Docs
+		externalDocsIndex := findMetadata(route.Metadata, EXTERNAL_DOCS)
+		if externalDocsIndex != -1 {
+			externalDocs, ok := route.Metadata[externalDocsIndex].Value.(*ExternalDocsObject)
+			if ok {
+				operation.ExternalDocs = externalDocs
+			}
+		}
+
+		// Api Deprecated
+		deprecatedIndex := findMetadata(route.Metadata, DEPRECATED)
+		if deprecatedIndex != -1 {
+			deprecated, ok := route.Metadata[deprecatedIndex].Value.(bool)
+			if ok {
+				operation.Deprecated = deprecated
+			}
+		}
+
+		// Api Version
+		applyVersion(operation, route, versioning)
+		if spec.versionTags && route.Version != "" {
+			operation.Tags = append(slices.Clone(operation.Tags), "v"+route.Version)
+		}
+
 		// Matching method
+		var slot **OperationObject
 		switch parseRoute.Method {
 		case "GET":
-			itemObject.Get = operation
+			slot = &itemObject.Get
 		case "POST":
-			itemObject.Post = operation
+			slot = &itemObject.Post
 		case "PUT":
-			itemObject.Put = operation
+			slot = &itemObject.Put
 		case "PATCH":
-			itemObject.Patch = operation
+			slot = &itemObject.Patch
 		case "DELETE":
-			itemObject.Delete = operation
+			slot = &itemObject.Delete
+		default:
+			continue
+		}
+		// Typically several versions of a route, documented one at a time
+		// with ForVersion
+		if *slot != nil {
+			registry.diagnostics = append(registry.diagnostics, Diagnostic{
+				Kind:    DiagnosticRouteConflict,
+				Route:   parseRoute.GetPath(),
+				Message: "route is registered more than once, only the last one is documented",
+			})
 		}
+		*slot = operation
 	}
 
 	// spec.Definitions = definitions
-	spec.Components.Schemas = schemas
+	spec.Components.Schemas = registry.resolve()
 	spec.Paths = pathObject
+	spec.Diagnostics = registry.diagnostics
+	spec.collectTags()
+}
+
+// collectTags rebuilds the document tags from the declared tags and the
+// tags used by the current operations, sorted according to the tag order.
+// Tags which are neither declared nor used any more are dropped.
+func (spec *SpecBuilder) collectTags() {
+	tags := slices.Clone(spec.declaredTags)
+
+	paths := make([]string, 0, len(spec.Paths))
+	for path := range spec.Paths {
+		paths = append(paths, path)
+	}
+	slices.Sort(paths)
+
+	for _, path := range paths {
+		for _, operation := range spec.Paths[path].operations() {
+			for _, name := range operation.Tags {
+				if findTag(tags, name) == nil {
+					tags = append(tags, &TagObject{Name: name})
+				}
+			}
+		}
+	}
+
+	slices.SortStableFunc(tags, func(a, b *TagObject) int {
+		return tagRank(spec.tagOrder, a.Name) - tagRank(spec.tagOrder, b.Name)
+	})
+	spec.Tags = tags
+}
+
+func tagRank(order []string, name string) int {
+	if idx := slices.Index(order, name); idx != -1 {
+		return idx
+	}
+	return len(order)
 }
 
 type Mapper map[string]interface{}
 
+// findMetadata returns the index of the metadata with the given key which
+// takes precedence, or -1 if there is none.
+//
+// A route's metadata lists the module metadata first, then the controller
+// metadata and finally the metadata declared on the route itself, so the last
+// match is the most specific one.
+func findMetadata(metadata []*core.Metadata, key string) int {
+	for i := len(metadata) - 1; i >= 0; i-- {
+		if metadata[i].Key == key {
+			return i
+		}
+	}
+	return -1
+}
+
+// metadataValues returns the values of all metadata with the given key, in
+// declaration order.
+func metadataValues(metadata []*core.Metadata, key string) []interface{} {
+	var values []interface{}
+	for _, m := range metadata {
+		if m.Key == key {
+			values = append(values, m.Value)
+		}
+	}
+	return values
+}
+
+// ownMetadata returns the index of the first metadata declared on the route
+// at index i itself. The metadata of its module and controller come first and
+// are shared with the neighbouring routes, the metadata of a controller with a
+// single route are taken as declared on the route.
+func ownMetadata(routes []*core.Router, i int) int {
+	own := 0
+	for _, j := range []int{i - 1, i + 1} {
+		if j < 0 || j >= len(routes) {
+			continue
+		}
+		shared := 0
+		for shared < len(routes[i].Metadata) && shared < len(routes[j].Metadata) &&
+			routes[i].Metadata[shared] == routes[j].Metadata[shared] {
+			shared++
+		}
+		own = max(own, shared)
+	}
+	return own
+}
+
+// encodeExamples builds the examples declared with the given metadata key.
+// Values are encoded with the given encoder, values which cannot be encoded
+// are reported as diagnostics and skipped.
+func encodeExamples(metadata []*core.Metadata, key string, encoder core.Encode, registry *schemaRegistry) map[string]*ExampleObject {
+	examples := make(map[string]*ExampleObject)
+	for _, v := range metadataValues(metadata, key) {
+		example, ok := v.(namedExample)
+		if !ok {
+			continue
+		}
+		value, err := encoder(example.Value)
+		if err != nil {
+			registry.diagnostics = append(registry.diagnostics, Diagnostic{
+				Kind:    DiagnosticExampleEncoding,
+				Route:   registry.route,
+				Message: fmt.Sprintf("example %q cannot be encoded: %v", example.Name, err),
+			})
+			continue
+		}
+		examples[example.Name] = &ExampleObject{
+			Summary: example.Name,
+			Value:   json.RawMessage(value),
+		}
+	}
+	return examples
+}
+
 // ParseSchema recursively parses a struct into a SchemaObject definition.
 func ParseSchema(dto any) *SchemaObject {
 	if dto == nil {
@@ -255,15 +434,6 @@ func ParseSchema(dto any) *SchemaObject {
 			requiredFields = append(requiredFields, fieldName)
 		}
 
-		// Parse example
-		if example := fieldType.Tag.Get("example"); example != "" {
-			if schema.Type == "array" {
-				schema.Example = strings.Split(example, ",")
-			} else {
-				schema.Example = example
-			}
-		}
-
 		// Handle nested fields
 		if slices.Contains(validations, "nested") {
 			schema = parseNested(fieldValue, fieldType.Type)
@@ -272,14 +442,91 @@ func ParseSchema(dto any) *SchemaObject {
 			schema.Items = &ItemsObject{Type: mappingType(elemType)}
 		}
 
+		// Parse example, a schema only holds one so the first named one is used
+		// when there is no example tag
+		if example := fieldType.Tag.Get("example"); example != "" {
+			schema.Example = parseValue(example, fieldType.Type)
+		} else if examples := parseExamples(fieldType.Tag.Get("examples"), fieldType.Type); len(examples) > 0 {
+			schema.Example = examples[0].Value
+		}
+
+		// Parse documentation tags
+		applyFieldTags(schema, parseFieldTags(fieldType), fieldType.Type)
+
 		properties[fieldName] = schema
 	}
 
-	return &SchemaObject{
+	schema := &SchemaObject{
 		Type:       "object",
 		Properties: properties,
 		Required:   requiredFields,
 	}
+	if documenter, ok := dto.(ExternalDocumenter); ok {
+		schema.ExternalDocs = documenter.ExternalDocs()
+	}
+	return schema
+}
+
+// refSchema returns the schema describing val as a response payload.
+//
+// Named structs are registered in the registry and referenced with $ref, while
+// slices, maps and primitives are described inline so that values such as
+// []User{} or "" do not produce bogus components.
+func refSchema(val any, registry *schemaRegistry) *SchemaObject {
+	if val == nil {
+		return nil
+	}
+	schema := typeSchema(reflect.TypeOf(val), registry)
+	registry.refs = append(registry.refs, schema)
+	return schema
+}
+
+func typeSchema(t reflect.Type, registry *schemaRegistry) *SchemaObject {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	switch {
+	case isTimeType(t):
+		return &SchemaObject{Type: "string", Format: "date-time"}
+	case t.Kind() == reflect.Struct:
+		// Anonymous structs have no name to reference
+		if t.Name() == "" {
+			return ParseSchema(reflect.New(t).Interface())
+		}
+		return &SchemaObject{Ref: registry.register(t)}
+	case t.Kind() == reflect.Slice && t.Elem().Kind() == reflect.Uint8:
+		// encoding/json writes []byte as a base64 string
+		return &SchemaObject{Type: "string", Format: "byte"}
+	case t.Kind() == reflect.Slice || t.Kind() == reflect.Array:
+		return &SchemaObject{
+			Type:  "array",
+			Items: schemaItems(typeSchema(t.Elem(), registry)),
+		}
+	case t.Kind() == reflect.Map:
+		return &SchemaObject{
+			Type:                 "object",
+			AdditionalProperties: typeSchema(t.Elem(), registry),
+		}
+	case t.Kind() == reflect.Interface:
+		return &SchemaObject{}
+	default:
+		return &SchemaObject{Type: mappingType(t)}
+	}
+}
+
+// schemaItems converts a schema into the items object of an array.
+func schemaItems(schema *SchemaObject) *ItemsObject {
+	return &ItemsObject{
+		Type:                 schema.Type,
+		Ref:                  schema.Ref,
+		Format:               schema.Format,
+		Required:             schema.Required,
+		Enum:                 schema.Enum,
+		Items:                schema.Items,
+		Properties:           schema.Properties,
+		AdditionalProperties: schema.AdditionalProperties,
+	}
 }
 
 // --- helpers ---
@@ -386,11 +633,24 @@ func ScanQuery(val interface{}, in core.CtxKey) []*ParameterObject {
 		} else {
 			param.Required = true
 		}
-		example := field.Tag.Get("example")
-		if example != "" {
-			param.Default = example
+		// example and examples are mutually exclusive, examples wins
+		if examples := parseExamples(field.Tag.Get("examples"), field.Type); len(examples) > 0 {
+			param.Examples = make(map[string]*ExampleObject)
+			for _, e := range examples {
+				param.Examples[e.Name] = &ExampleObject{Value: e.Value}
+			}
+		} else if example := field.Tag.Get("example"); example != "" {
+			param.Example = parseValue(example, field.Type)
 		}
 
+		// Description and deprecation belong to the parameter, the rest to its schema
+		opts := parseFieldTags(field)
+		param.Description = opts["description"]
+		param.Deprecated, _ = strconv.ParseBool(opts["deprecated"])
+		delete(opts, "description")
+		delete(opts, "deprecated")
+		applyFieldTags(param.Schema, opts, field.Type)
+
 		params = append(params, param)
 	}
 
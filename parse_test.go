@@ -67,7 +67,7 @@ func Test_ParseDefinition(t *testing.T) {
 	asrt.Equal("abc", defintion.Properties["name"].Example)
 	asrt.NotNil(defintion.Properties["age"])
 	asrt.Equal("integer", defintion.Properties["age"].Type)
-	asrt.Equal("12", defintion.Properties["age"].Example)
+	asrt.Equal(int64(12), defintion.Properties["age"].Example)
 	asrt.Nil(defintion.Properties["hidden"])
 }
 
@@ -143,5 +143,5 @@ func Test_ComplexStruct(t *testing.T) {
 
 	text, err := json.Marshal(defintion)
 	require.Nil(t, err)
-	require.Equal(t, `{"type":"object","properties":{"category":{"type":"string","example":"paid-time-off"},"config":{"type":"object","properties":{"accrualPolicy":{"type":"object","properties":{"accrualMethod":{"type":"string","example":"year"},"accrualRates":{"type":"array","items":{"type":"object","properties":{"from":{"type":"integer","example":"0"},"to":{"type":"integer","example":"100"},"value":{"type":"integer","example":"12"}}}}}},"allowedApplyFuture":{"type":"boolean","example":"true"},"annualResetPolicy":{"type":"object","properties":{"date":{"type":"string","example":"2024-01-01"},"type":{"type":"string","example":"calendarDate"}}},"autoApproval":{"type":"object","properties":{"expireDuration":{"type":"integer","example":"72"},"isEnable":{"type":"boolean","example":"true"},"leaveAmount":{"type":"number","example":"3"}}},"carryForwardPolicy":{"type":"object","properties":{"carryForwardRates":{"type":"array","items":{"type":"object","properties":{"from":{"type":"integer","example":"0"},"to":{"type":"integer","example":"100"},"value":{"type":"integer","example":"12"}}}},"expireDuration":{"type":"integer","example":"90"}}},"emailReminder":{"type":"object","properties":{"expireDuration":{"type":"integer","example":"24"},"isEnable":{"type":"boolean","example":"true"}}},"leaveApplicationStart":{"type":"integer","example":"60"},"maxLeaveAmount":{"type":"number","example":"5"},"minLeaveAmount":{"type":"number","example":"0.5"},"newHireProbationPolicy":{"type":"object","properties":{"isEnable":{"type":"boolean","example":"false"},"rules":{"type":"array","items":{"type":"object","properties":{"from":{"type":"integer","example":"0"},"to":{"type":"integer","example":"100"},"value":{"type":"integer","example":"12"}}}}}},"timeUnit":{"type":"string","example":"d"}}},"country":{"type":"string","example":"US"},"locationId":{"type":"string","example":"3fa85f64-5717-4562-b3fc-2c963f66afa6"},"name":{"type":"string","example":"Annual Leave"},"requiredInfo":{"type":"object","properties":{"employeeType":{"type":"string","example":"full-time"},"gender":{"type":"string","example":"male"}}}}}`, string(text))
+	require.Equal(t, `{"type":"object","properties":{"category":{"type":"string","example":"paid-time-off"},"config":{"type":"object","properties":{"accrualPolicy":{"type":"object","properties":{"accrualMethod":{"type":"string","example":"year"},"accrualRates":{"type":"array","items":{"type":"object","properties":{"from":{"type":"integer","example":0},"to":{"type":"integer","example":100},"value":{"type":"integer","example":12}}}}}},"allowedApplyFuture":{"type":"boolean","example":true},"annualResetPolicy":{"type":"object","properties":{"date":{"type":"string","example":"2024-01-01"},"type":{"type":"string","example":"calendarDate"}}},"autoApproval":{"type":"object","properties":{"expireDuration":{"type":"integer","example":72},"isEnable":{"type":"boolean","example":true},"leaveAmount":{"type":"number","example":3}}},"carryForwardPolicy":{"type":"object","properties":{"carryForwardRates":{"type":"array","items":{"type":"object","properties":{"from":{"type":"integer","example":0},"to":{"type":"integer","example":100},"value":{"type":"integer","example":12}}}},"expireDuration":{"type":"integer","example":90}}},"emailReminder":{"type":"object","properties":{"expireDuration":{"type":"integer","example":24},"isEnable":{"type":"boolean","example":true}}},"leaveApplicationStart":{"type":"integer","example":60},"maxLeaveAmount":{"type":"number","example":5},"minLeaveAmount":{"type":"number","example":0.5},"newHireProbationPolicy":{"type":"object","properties":{"isEnable":{"type":"boolean","example":false},"rules":{"type":"array","items":{"type":"object","properties":{"from":{"type":"integer","example":0},"to":{"type":"integer","example":100},"value":{"type":"integer","example":12}}}}}},"timeUnit":{"type":"string","example":"d"}}},"country":{"type":"string","example":"US"},"locationId":{"type":"string","example":"3fa85f64-5717-4562-b3fc-2c963f66afa6"},"name":{"type":"string","example":"Annual Leave"},"requiredInfo":{"type":"object","properties":{"employeeType":{"type":"string","example":"full-time"},"gender":{"type":"string","example":"male"}}}}}`, string(text))
 }
@@ -0,0 +1,93 @@
+package swagger
+
+import (
+	"crypto/subtle"
+	"net/http"
+	"os"
+	"slices"
+	"strconv"
+
+	"github.com/tinh-tinh/tinhtinh/v2/common"
+	"github.com/tinh-tinh/tinhtinh/v2/core"
+)
+
+type BasicAuth struct {
+	Username string
+	Password string
+	// Realm is sent in the WWW-Authenticate header. Defaults to
+	// "Documentation".
+	Realm string
+}
+
+// EnabledByEnv returns a Config.Enabled func reading the environment
+// variable key on each request. The documentation is enabled when the
+// variable equals one of values, or when no values are given and it parses
+// as true:
+//
+//	swagger.Config{Enabled: swagger.EnabledByEnv("APP_ENV", "development", "staging")}
+//	swagger.Config{Enabled: swagger.EnabledByEnv("DOCS_ENABLED")}
+func EnabledByEnv(key string, values ...string) func() bool {
+	return func() bool {
+		value := os.Getenv(key)
+		if len(values) == 0 {
+			enabled, _ := strconv.ParseBool(value)
+			return enabled
+		}
+		return slices.Contains(values, value)
+	}
+}
+
+// handle registers a documentation endpoint on the app, protected as
+// configured.
+func (config Config) handle(app *core.App, pattern string, handler http.Handler) {
+	app.Mux.Handle(pattern, config.protect(app, handler))
+}
+
+// protect wraps handler with the checks of the config, run in order:
+// Enabled, BasicAuth, Guard and then Middleware.
+func (config Config) protect(app *core.App, handler http.Handler) http.Handler {
+	if config.Middleware != nil {
+		handler = config.Middleware(handler)
+	}
+	if config.Guard != nil {
+		next := handler
+		handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			ctx := core.NewCtx(app)
+			ctx.SetCtx(w, r)
+			if !config.Guard(ctx) {
+				_ = common.ForbiddenException(ctx.Res(), "you can not access")
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+	if auth := config.BasicAuth; auth != nil {
+		next := handler
+		realm := auth.Realm
+		if realm == "" {
+			realm = "Documentation"
+		}
+		handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			username, password, ok := r.BasicAuth()
+			if !ok ||
+				subtle.ConstantTimeCompare([]byte(username), []byte(auth.Username)) != 1 ||
+				subtle.ConstantTimeCompare([]byte(password), []byte(auth.Password)) != 1 {
+				w.Header().Set("WWW-Authenticate", "Basic realm="+strconv.Quote(realm))
+				http.Error(w, http.StatusText(http.StatusUnauthorized), http.StatusUnauthorized)
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+	if config.Enabled != nil {
+		next := handler
+		handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if !config.Enabled() {
+				http.NotFound(w, r)
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+	return handler
+}
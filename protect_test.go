@@ -0,0 +1,118 @@
+package swagger_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/tinh-tinh/swagger/v2"
+	"github.com/tinh-tinh/tinhtinh/v2/core"
+)
+
+var docsPaths = []string{
+	"/api/swagger",
+	"/api/swagger/init.js",
+	"/api/swagger/assets/swagger-ui.css",
+	"/api/swagger/openapi.json",
+	"/api/swagger/openapi.yaml",
+}
+
+func setUpProtected(t *testing.T, config swagger.Config) *httptest.Server {
+	server := core.CreateFactory(AppModule)
+	server.SetGlobalPrefix("api")
+
+	swagger.SetUp("/swagger", server, swagger.NewSpecBuilder(), config)
+	testServer := httptest.NewServer(server.PrepareBeforeListen())
+	t.Cleanup(testServer.Close)
+	return testServer
+}
+
+func requireStatus(t *testing.T, testServer *httptest.Server, status int, header ...string) {
+	for _, path := range docsPaths {
+		req, err := http.NewRequest("GET", testServer.URL+path, nil)
+		require.Nil(t, err)
+		if len(header) == 2 {
+			req.Header.Set(header[0], header[1])
+		}
+		resp, err := testServer.Client().Do(req)
+		require.Nil(t, err)
+		require.Equal(t, status, resp.StatusCode, path)
+	}
+}
+
+func Test_Enabled(t *testing.T) {
+	enabled := false
+	testServer := setUpProtected(t, swagger.Config{
+		Enabled: func() bool { return enabled },
+	})
+	requireStatus(t, testServer, http.StatusNotFound)
+
+	enabled = true
+	requireStatus(t, testServer, http.StatusOK)
+}
+
+func Test_EnabledByEnv(t *testing.T) {
+	t.Setenv("APP_ENV", "production")
+	require.False(t, swagger.EnabledByEnv("APP_ENV", "development", "staging")())
+	t.Setenv("APP_ENV", "staging")
+	require.True(t, swagger.EnabledByEnv("APP_ENV", "development", "staging")())
+
+	t.Setenv("DOCS_ENABLED", "")
+	require.False(t, swagger.EnabledByEnv("DOCS_ENABLED")())
+	t.Setenv("DOCS_ENABLED", "true")
+	require.True(t, swagger.EnabledByEnv("DOCS_ENABLED")())
+}
+
+func Test_BasicAuth(t *testing.T) {
+	testServer := setUpProtected(t, swagger.Config{
+		BasicAuth: &swagger.BasicAuth{Username: "docs", Password: "secret"},
+	})
+	requireStatus(t, testServer, http.StatusUnauthorized)
+
+	resp, err := testServer.Client().Get(testServer.URL + "/api/swagger")
+	require.Nil(t, err)
+	require.Equal(t, `Basic realm="Documentation"`, resp.Header.Get("WWW-Authenticate"))
+
+	req, err := http.NewRequest("GET", testServer.URL+"/api/swagger", nil)
+	require.Nil(t, err)
+	req.SetBasicAuth("docs", "wrong")
+	resp, err = testServer.Client().Do(req)
+	require.Nil(t, err)
+	require.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+
+	for _, path := range docsPaths {
+		req, err := http.NewRequest("GET", testServer.URL+path, nil)
+		require.Nil(t, err)
+		req.SetBasicAuth("docs", "secret")
+		resp, err := testServer.Client().Do(req)
+		require.Nil(t, err)
+		require.Equal(t, http.StatusOK, resp.StatusCode, path)
+	}
+}
+
+func Test_DocsGuard(t *testing.T) {
+	testServer := setUpProtected(t, swagger.Config{
+		Guard: func(ctx core.Ctx) bool {
+			return ctx.Headers("X-Docs-Token") == "token"
+		},
+	})
+	requireStatus(t, testServer, http.StatusForbidden)
+	requireStatus(t, testServer, http.StatusOK, "X-Docs-Token", "token")
+}
+
+func Test_DocsMiddleware(t *testing.T) {
+	testServer := setUpProtected(t, swagger.Config{
+		Middleware: func(next http.Handler) http.Handler {
+			return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.Header.Get("X-Internal") == "" {
+					http.Error(w, "teapot", http.StatusTeapot)
+					return
+				}
+				next.ServeHTTP(w, r)
+			})
+		},
+	})
+	requireStatus(t, testServer, http.StatusTeapot)
+	requireStatus(t, testServer, http.StatusOK, "X-Internal", "1")
+}
@@ -7,3 +7,12 @@ const OK_RESPONSE = "ok_response"
 func ApiOkResponse(val interface{}) *core.Metadata {
 	return core.SetMetadata(OK_RESPONSE, val)
 }
+
+const OK_RESPONSE_EXAMPLE = "ok_response_example"
+
+// ApiOkResponseExample adds a named example of the ok response payload. The
+// value is encoded with the app encoder, so it can be any Go value the
+// handler would return.
+func ApiOkResponseExample(name string, value interface{}) *core.Metadata {
+	return core.SetMetadata(OK_RESPONSE_EXAMPLE, namedExample{Name: name, Value: value})
+}
@@ -0,0 +1,174 @@
+package swagger
+
+import (
+	"encoding/json"
+	"maps"
+
+	"github.com/tinh-tinh/tinhtinh/v2/core"
+)
+
+// SecurityRequirement lists the security schemes, with their required
+// scopes, which must all be satisfied to call an operation.
+type SecurityRequirement map[string][]string
+
+// Require returns a requirement on the given security scheme and scopes.
+func Require(name string, scopes ...string) SecurityRequirement {
+	return SecurityRequirement{name: scopes}
+}
+
+// And returns a copy of the requirement which also requires the given
+// security scheme and scopes.
+func (r SecurityRequirement) And(name string, scopes ...string) SecurityRequirement {
+	requirement := make(SecurityRequirement, len(r)+1)
+	for k, v := range r {
+		requirement[k] = v
+	}
+	requirement[name] = scopes
+	return requirement
+}
+
+// BearerAuth returns an http bearer security scheme named bearerAuth. The
+// bearer format defaults to JWT.
+func BearerAuth(bearerFormat ...string) *SecuritySchemeObject {
+	format := "JWT"
+	if len(bearerFormat) > 0 {
+		format = bearerFormat[0]
+	}
+	return &SecuritySchemeObject{
+		Type:         "http",
+		Scheme:       "bearer",
+		BearerFormat: format,
+		Name:         "bearerAuth",
+	}
+}
+
+// ApiKeyAuth returns an apiKey security scheme reading the key from the given
+// header, query parameter or cookie. It is registered under its name.
+func ApiKeyAuth(in string, name string) *SecuritySchemeObject {
+	return &SecuritySchemeObject{
+		Type: "apiKey",
+		In:   in,
+		Name: name,
+	}
+}
+
+// OAuth2 returns an oauth2 security scheme named oauth2 with the given flows.
+// The flows are copied, so that the caller's values are left untouched.
+func OAuth2(flows *OAuthFlowsObject) *SecuritySchemeObject {
+	copied := &OAuthFlowsObject{}
+	if flows != nil {
+		copied = &OAuthFlowsObject{
+			Implicit:          copyFlow(flows.Implicit),
+			Password:          copyFlow(flows.Password),
+			ClientCredentials: copyFlow(flows.ClientCredentials),
+			AuthorizationCode: copyFlow(flows.AuthorizationCode),
+		}
+	}
+	return &SecuritySchemeObject{
+		Type:  "oauth2",
+		Flows: copied,
+		Name:  "oauth2",
+	}
+}
+
+// copyFlow copies an OAuth flow, with empty scopes when it has none since
+// OpenAPI requires them.
+func copyFlow(flow *OAuthFlowObject) *OAuthFlowObject {
+	if flow == nil {
+		return nil
+	}
+	copied := *flow
+	copied.Scopes = maps.Clone(flow.Scopes)
+	if copied.Scopes == nil {
+		copied.Scopes = map[string]string{}
+	}
+	return &copied
+}
+
+// OpenIDConnect returns an openIdConnect security scheme named openIdConnect
+// discovering its configuration from the given url.
+func OpenIDConnect(url string) *SecuritySchemeObject {
+	return &SecuritySchemeObject{
+		Type:             "openIdConnect",
+		OpenIdConnectUrl: url,
+		Name:             "openIdConnect",
+	}
+}
+
+// MarshalJSON only writes the name of apiKey schemes. Dropping it for the
+// other types is intentional: OpenAPI only defines name for apiKey schemes
+// and does not allow other fields, while the helpers of this file set it on
+// every scheme as the component name used by AddSecurity.
+func (s SecuritySchemeObject) MarshalJSON() ([]byte, error) {
+	type securityScheme SecuritySchemeObject
+	if s.Type != "apiKey" {
+		s.Name = ""
+	}
+	return json.Marshal(securityScheme(s))
+}
+
+// MarshalJSON writes an empty security list, which marks a public operation,
+// instead of omitting it. A nil list is omitted.
+func (op OperationObject) MarshalJSON() ([]byte, error) {
+	type operation OperationObject
+	if op.Security != nil && len(op.Security) == 0 {
+		return json.Marshal(struct {
+			operation
+			Security []map[string][]string `json:"security"`
+		}{operation(op), op.Security})
+	}
+	return json.Marshal(operation(op))
+}
+
+// routeSecurity resolves the security of a route, from the most specific
+// declaration to the least: the security metadata declared on the route
+// itself from index own, the requirement of its mapped guards, then the
+// security metadata of its controller or module. It returns nil when the
+// route inherits the global security.
+func (spec *SpecBuilder) routeSecurity(route *core.Router, own int, guard SecurityRequirement) []map[string][]string {
+	index := findMetadata(route.Metadata, SECURITY)
+	if index != -1 && index >= own {
+		security, _ := parseSecurity(route.Metadata[index].Value)
+		return security
+	}
+	if guard != nil {
+		security, _ := parseSecurity([]SecurityRequirement{guard})
+		return security
+	}
+	if index != -1 {
+		security, _ := parseSecurity(route.Metadata[index].Value)
+		return security
+	}
+	if spec.guardDetection && len(spec.Security) > 0 && len(guardNames(route)) == 0 {
+		return []map[string][]string{}
+	}
+	return nil
+}
+
+// parseSecurity converts the value of security metadata into the security
+// requirements of an operation.
+func parseSecurity(value interface{}) ([]map[string][]string, bool) {
+	switch v := value.(type) {
+	case []string:
+		security := map[string][]string{}
+		for _, s := range v {
+			security[s] = []string{}
+		}
+		return []map[string][]string{security}, true
+	case []SecurityRequirement:
+		requirements := make([]map[string][]string, 0, len(v))
+		for _, requirement := range v {
+			security := map[string][]string{}
+			for name, scopes := range requirement {
+				if scopes == nil {
+					scopes = []string{}
+				}
+				security[name] = scopes
+			}
+			requirements = append(requirements, security)
+		}
+		return requirements, true
+	default:
+		return nil, false
+	}
+}
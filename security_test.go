@@ -0,0 +1,139 @@
+package swagger
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_SecuritySchemes(t *testing.T) {
+	spec := NewSpecBuilder().AddSecurity(
+		BearerAuth(),
+		ApiKeyAuth("header", "X-API-Key"),
+		OAuth2(&OAuthFlowsObject{
+			AuthorizationCode: &OAuthFlowObject{
+				AuthorizationUrl: "https://auth.example.com/authorize",
+				TokenUrl:         "https://auth.example.com/token",
+				Scopes:           map[string]string{"orders:write": "Create orders"},
+			},
+			ClientCredentials: &OAuthFlowObject{
+				TokenUrl: "https://auth.example.com/token",
+			},
+		}),
+		OpenIDConnect("https://auth.example.com/.well-known/openid-configuration"),
+	).AddSecurityScheme("adminAuth", BearerAuth("opaque"))
+
+	schemes := spec.Components.SecuritySchemes
+	require.Len(t, schemes, 5)
+	assert.Equal(t, "JWT", schemes["bearerAuth"].BearerFormat)
+	assert.Equal(t, "opaque", schemes["adminAuth"].BearerFormat)
+	assert.Equal(t, "header", schemes["X-API-Key"].In)
+	assert.NotNil(t, schemes["oauth2"].Flows.ClientCredentials.Scopes)
+	assert.Equal(t, "openIdConnect", schemes["openIdConnect"].Type)
+
+	data, err := json.Marshal(schemes["bearerAuth"])
+	require.Nil(t, err)
+	assert.JSONEq(t, `{"type":"http","scheme":"bearer","bearerFormat":"JWT"}`, string(data))
+
+	data, err = json.Marshal(schemes["X-API-Key"])
+	require.Nil(t, err)
+	assert.JSONEq(t, `{"type":"apiKey","in":"header","name":"X-API-Key"}`, string(data))
+
+	// The component name of the other types is not written
+	for _, name := range []string{"oauth2", "openIdConnect"} {
+		data, err = json.Marshal(schemes[name])
+		require.Nil(t, err)
+		assert.NotContains(t, string(data), `"name"`, name)
+		assert.Equal(t, name, schemes[name].Name)
+	}
+
+	data, err = json.Marshal(spec.Components)
+	require.Nil(t, err)
+	components := openapi3.Components{}
+	require.Nil(t, json.Unmarshal(data, &components))
+	for name, scheme := range components.SecuritySchemes {
+		assert.Nil(t, scheme.Value.Validate(context.Background()), name)
+	}
+}
+
+func Test_OAuth2(t *testing.T) {
+	assert.NotPanics(t, func() {
+		scheme := OAuth2(nil)
+		assert.NotNil(t, scheme.Flows)
+	})
+
+	flows := &OAuthFlowsObject{
+		Password: &OAuthFlowObject{TokenUrl: "https://auth.example.com/token"},
+		Implicit: &OAuthFlowObject{
+			AuthorizationUrl: "https://auth.example.com/authorize",
+			Scopes:           map[string]string{"orders:read": "Read orders"},
+		},
+	}
+	scheme := OAuth2(flows)
+	scheme.Flows.Implicit.Scopes["orders:write"] = "Create orders"
+
+	assert.Nil(t, flows.Password.Scopes)
+	assert.Equal(t, map[string]string{}, scheme.Flows.Password.Scopes)
+	assert.Equal(t, map[string]string{"orders:read": "Read orders"}, flows.Implicit.Scopes)
+	assert.Nil(t, scheme.Flows.ClientCredentials)
+}
+
+func Test_ParseSecurity(t *testing.T) {
+	security, ok := parseSecurity([]string{"bearerAuth", "apiKey"})
+	assert.True(t, ok)
+	assert.Equal(t, []map[string][]string{{"bearerAuth": {}, "apiKey": {}}}, security)
+
+	security, ok = parseSecurity(ApiSecurityScopes("oauth2", "orders:read", "orders:write").Value)
+	assert.True(t, ok)
+	assert.Equal(t, []map[string][]string{{"oauth2": {"orders:read", "orders:write"}}}, security)
+
+	security, ok = parseSecurity([]SecurityRequirement{{"bearerAuth": nil}})
+	assert.True(t, ok)
+	assert.Equal(t, []map[string][]string{{"bearerAuth": {}}}, security)
+
+	_, ok = parseSecurity("bearerAuth")
+	assert.False(t, ok)
+}
+
+func Test_SecurityRequirements(t *testing.T) {
+	requirement := Require("bearerAuth")
+	combined := requirement.And("apiKey")
+	assert.Equal(t, SecurityRequirement{"bearerAuth": nil}, requirement)
+	assert.Equal(t, SecurityRequirement{"bearerAuth": nil, "apiKey": nil}, combined)
+
+	security, ok := parseSecurity(ApiSecurityAny(
+		Require("oauth2", "orders:write"),
+		Require("bearerAuth").And("apiKey"),
+	).Value)
+	assert.True(t, ok)
+	assert.Equal(t, []map[string][]string{
+		{"oauth2": {"orders:write"}},
+		{"bearerAuth": {}, "apiKey": {}},
+	}, security)
+
+	security, ok = parseSecurity(ApiPublic().Value)
+	assert.True(t, ok)
+	assert.NotNil(t, security)
+	assert.Empty(t, security)
+}
+
+func Test_PublicOperation(t *testing.T) {
+	data, err := json.Marshal(&OperationObject{Security: []map[string][]string{}})
+	require.Nil(t, err)
+	assert.JSONEq(t, `{"security":[],"responses":null}`, string(data))
+
+	data, err = json.Marshal(&OperationObject{})
+	require.Nil(t, err)
+	assert.JSONEq(t, `{"responses":null}`, string(data))
+
+	operation := openapi3.Operation{}
+	data, err = json.Marshal(&OperationObject{Security: []map[string][]string{}, Responses: map[string]*ResponseObject{}})
+	require.Nil(t, err)
+	require.Nil(t, json.Unmarshal(data, &operation))
+	require.NotNil(t, operation.Security)
+	assert.Empty(t, *operation.Security)
+}
@@ -5,6 +5,9 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"reflect"
+	"strings"
+	"unsafe"
 
 	"github.com/getkin/kin-openapi/openapi3"
 	"github.com/tinh-tinh/tinhtinh/v2/core"
@@ -56,6 +59,8 @@ func (spec *SpecBuilder) SetServer(server *ServerObject) *SpecBuilder {
 	return spec
 }
 
+// AddSecurity registers security schemes under their name, see BearerAuth,
+// ApiKeyAuth, OAuth2 and OpenIDConnect.
 func (spec *SpecBuilder) AddSecurity(security ...*SecuritySchemeObject) *SpecBuilder {
 	for _, v := range security {
 		spec.Components.SecuritySchemes[v.Name] = v
@@ -63,6 +68,116 @@ func (spec *SpecBuilder) AddSecurity(security ...*SecuritySchemeObject) *SpecBui
 	return spec
 }
 
+// SetGlobalSecurity sets the security required by every operation of the
+// document. Routes override it with ApiSecurity or opt out with ApiPublic.
+func (spec *SpecBuilder) SetGlobalSecurity(requirements ...SecurityRequirement) *SpecBuilder {
+	spec.Security, _ = parseSecurity(requirements)
+	return spec
+}
+
+// SetGuardDetection documents the routes without any guard named with
+// ApiGuard as public, so that the global security only applies to guarded
+// routes. Routes declaring their security with metadata are left untouched.
+func (spec *SpecBuilder) SetGuardDetection(enabled bool) *SpecBuilder {
+	spec.guardDetection = enabled
+	return spec
+}
+
+// AddSecurityScheme registers a security scheme under the given name, for
+// example to declare two bearer schemes.
+func (spec *SpecBuilder) AddSecurityScheme(name string, security *SecuritySchemeObject) *SpecBuilder {
+	spec.Components.SecuritySchemes[name] = security
+	return spec
+}
+
+// SetExternalDocs links additional documentation from the whole API.
+func (spec *SpecBuilder) SetExternalDocs(url string, description string) *SpecBuilder {
+	spec.ExternalDocs = &ExternalDocsObject{Url: url, Description: description}
+	return spec
+}
+
+// AddTag declares a tag with its description and optional external
+// documentation. Declaring an existing tag again updates it.
+//
+// Tags used by operations but not declared are added by ParsePaths, after
+// the declared ones, and dropped by the next ParsePaths once no operation
+// uses them.
+func (spec *SpecBuilder) AddTag(name string, description string, externalDocs ...*ExternalDocsObject) *SpecBuilder {
+	tag := findTag(spec.declaredTags, name)
+	if tag == nil {
+		tag = &TagObject{Name: name}
+		spec.declaredTags = append(spec.declaredTags, tag)
+	}
+	tag.Description = description
+	if len(externalDocs) > 0 {
+		tag.ExternalDocs = externalDocs[0]
+	}
+	spec.collectTags()
+	return spec
+}
+
+// SetTagOrder sets the order of the tags in the document. Tags which are not
+// listed keep their order after the listed ones.
+func (spec *SpecBuilder) SetTagOrder(names ...string) *SpecBuilder {
+	spec.tagOrder = names
+	return spec
+}
+
+// AddTagGroup groups tags under a name in UIs supporting the x-tagGroups
+// extension, such as ReDoc.
+func (spec *SpecBuilder) AddTagGroup(name string, tags ...string) *SpecBuilder {
+	spec.TagGroups = append(spec.TagGroups, &TagGroupObject{Name: name, Tags: tags})
+	return spec
+}
+
+func findTag(tags []*TagObject, name string) *TagObject {
+	for _, tag := range tags {
+		if tag.Name == name {
+			return tag
+		}
+	}
+	return nil
+}
+
+// SetSchemaNaming sets the strategy used to name a schema whose struct name
+// is already taken by another type. Defaults to PackageQualifiedNaming.
+func (spec *SpecBuilder) SetSchemaNaming(strategy SchemaNamingStrategy) *SpecBuilder {
+	spec.schemaNaming = strategy
+	return spec
+}
+
+// SetEncoder overrides the encoder used to marshal example values. By default
+// examples are marshalled with the encoder given to core.CreateFactory, so
+// that they match real payloads.
+func (spec *SpecBuilder) SetEncoder(encoder core.Encode) *SpecBuilder {
+	spec.encoder = encoder
+	return spec
+}
+
+// encoderOf returns the encoder of example values: the one set with
+// SetEncoder, else the encoder of the app, else json.Marshal.
+func (spec *SpecBuilder) encoderOf(app *core.App) core.Encode {
+	if spec.encoder != nil {
+		return spec.encoder
+	}
+	if encoder := readEncoder(reflect.ValueOf(app).Elem()); encoder != nil {
+		return encoder
+	}
+	return json.Marshal
+}
+
+// readEncoder reads the encoder field of an app struct, which core.App keeps
+// unexported like its versioning. It returns nil when the field is not an
+// addressable core.Encode.
+func readEncoder(app reflect.Value) core.Encode {
+	field := app.FieldByName("encoder")
+	if !field.IsValid() || field.Type() != reflect.TypeOf(core.Encode(nil)) || !field.CanAddr() {
+		return nil
+	}
+	encoder, _ := reflect.NewAt(field.Type(), unsafe.Pointer(field.UnsafeAddr())).Elem().Interface().(core.Encode)
+	return encoder
+}
+
 // Build builds the swagger spec.
 //
 // It takes the SpecBuilder instance and returns the same instance
@@ -73,84 +188,194 @@ func (spec *SpecBuilder) Build() *SpecBuilder {
 
 // SetUp sets up the swagger UI and API endpoint.
 //
-// It takes a prefix to mount the swagger UI and API endpoint, an app
-// instance, and a SpecBuilder instance. It will parse the app's routes
-// using the SpecBuilder and generate the swagger spec. It will then
-// register a swagger handler with the app. The swagger handler will
-// serve the swagger UI and API endpoint.
+// It takes a path to mount the swagger UI, an app instance, and a
+// SpecBuilder instance. It will parse the app's routes using the
+// SpecBuilder and generate the swagger spec. It will then register
+// handlers with the app serving the swagger UI and the document.
 //
-// The swagger UI will be available at the path <prefix>/doc.json. The
-// swagger API endpoint will be available at the path <prefix>/doc.json.
+// The swagger UI will be available at <global prefix><path> and the
+// document at <global prefix><path>/openapi.json, or .yaml for YAML,
+// unless Config.DocumentPath is set.
 //
-// For example, if you call SetUp("/swagger", app, spec), you can access
-// the swagger UI at http://localhost:8080/swagger/doc.json and the
-// swagger API endpoint at http://localhost:8080/swagger/doc.json.
+// For example, if you call SetUp("/swagger", app, spec) on an app with the
+// global prefix "api", you can access the swagger UI at
+// http://localhost:8080/api/swagger and the document at
+// http://localhost:8080/api/swagger/openapi.json.
 func SetUp(path string, app *core.App, spec *SpecBuilder, configs ...Config) {
-	spec.ParsePaths(app)
-	jsonBytes, _ := json.Marshal(spec)
+	var config Config
+	if len(configs) > 0 {
+		config = configs[0]
+	}
+	route := fmt.Sprintf("%s%s", core.IfSlashPrefixString(app.Prefix), core.IfSlashPrefixString(path))
 
-	ctx := context.Background()
-	loader := &openapi3.Loader{Context: ctx, IsExternalRefsAllowed: true}
-	doc, err := loader.LoadFromData(jsonBytes)
+	documentPath := config.DocumentPath
+	if documentPath == "" {
+		documentPath = route + "/openapi.json"
+	}
+	documentURL := config.DocumentURL
+	if documentURL == "" {
+		documentURL = documentPath
+	}
+
+	jsonDoc, yamlDoc, err := buildDocument(app, spec)
 	if err != nil {
 		fmt.Println(err)
 		return
 	}
-	// Validate document
-	_ = doc.Validate(ctx)
+	serveDocument(app, documentPath, jsonDoc, yamlDoc, config)
 
-	// Serve the OpenAPI document as JSON
-	app.Mux.Handle("/openapi.json", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		w.Header().Set("Content-Type", "application/json")
-		if err := json.NewEncoder(w).Encode(doc); err != nil {
-			http.Error(w, "Failed to encode OpenAPI document", http.StatusInternalServerError)
-		}
-	}))
+	// Serve the documentation UI
+	mountUI(route, app, []documentLink{{Name: spec.Info.Title, URL: documentURL}}, config)
+}
 
+// Document is one of the documents served by SetUpDocuments.
+type Document struct {
+	// Name is listed by the UI, and names the document path.
+	Name string
+	Spec *SpecBuilder
+}
+
+// SetUpDocuments serves several documents of the same app, e.g. selecting
+// their routes with SpecBuilder.Include, under a single UI. Swagger UI lets
+// readers switch between them from a dropdown.
+//
+// Each document is served at <global prefix><path>/<name>/openapi.json, or
+// .yaml for YAML, the name being lowercased, its spaces replaced with dashes
+// and its other characters than a-z, 0-9 and dashes removed. Names giving the
+// same path segment are numbered, e.g. "public-2". Config.DocumentPath and
+// Config.DocumentURL are ignored.
+//
+// Every document is built before any route is registered: when one of them
+// cannot be built, the error is printed and nothing is served.
+//
+//	swagger.SetUpDocuments("/docs", app, []swagger.Document{
+//		{Name: "Public", Spec: publicSpec},
+//		{Name: "Partner", Spec: partnerSpec},
+//	})
+func SetUpDocuments(path string, app *core.App, documents []Document, configs ...Config) {
+	var config Config
+	if len(configs) > 0 {
+		config = configs[0]
+	}
 	route := fmt.Sprintf("%s%s", core.IfSlashPrefixString(app.Prefix), core.IfSlashPrefixString(path))
-	// Serve Swagger UI HTML from CDN
-	app.Mux.Handle(route, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		var persistAuth string
-		if len(configs) > 0 {
-			config := configs[0]
-			if config.PersistAuthorization {
-				persistAuth += "persistAuthorization: true,\n"
-			}
+
+	// Build every document before serving any of them, so that a broken
+	// document does not leave the others half mounted
+	type builtDocument struct {
+		link             documentLink
+		jsonDoc, yamlDoc []byte
+	}
+	built := make([]builtDocument, 0, len(documents))
+	slugs := make(map[string]bool, len(documents))
+	for _, document := range documents {
+		jsonDoc, yamlDoc, err := buildDocument(app, document.Spec)
+		if err != nil {
+			fmt.Printf("swagger: document %q: %v, no document is served\n", document.Name, err)
+			return
+		}
+
+		slug := documentSlug(document.Name)
+		for i := 2; slugs[slug]; i++ {
+			slug = fmt.Sprintf("%s-%d", documentSlug(document.Name), i)
 		}
-		htmlParser := fmt.Sprintf(`
-        <!DOCTYPE html>
-        <html lang="en">
-        <head>
-            <meta charset="UTF-8">
-            <meta name="viewport" content="width=device-width, initial-scale=1.0">
-            <title>Swagger UI</title>
-						<link href="https://static1.smartbear.co/swagger/media/assets/swagger_fav.png" type="image/png" rel="shortcut icon">
-						<!-- Load Swagger UI from CDN -->
-            <link rel="stylesheet" type="text/css" href="https://cdn.jsdelivr.net/npm/swagger-ui-dist@4.15.5/swagger-ui.css" />
-            <script src="https://cdn.jsdelivr.net/npm/swagger-ui-dist@4.15.5/swagger-ui-bundle.js"></script>
-            <script src="https://cdn.jsdelivr.net/npm/swagger-ui-dist@4.15.5/swagger-ui-standalone-preset.js"></script>
-        </head>
-        <body>
-            <div id="swagger-ui"></div>
-            <script>
-                const ui = SwaggerUIBundle({
-                    url: "/openapi.json",  // URL for your OpenAPI spec
-                    dom_id: '#swagger-ui',
-                    deepLinking: true,
-                    presets: [
-                        SwaggerUIBundle.presets.apis,
-                        SwaggerUIBundle.SwaggerUIStandalonePreset
-                    ],
-                    layout: "BaseLayout",
-					%s
-                });
-            </script>
-        </body>
-        </html>
-        `, persistAuth)
-		w.Header().Set("Content-Type", "text/html")
-		if _, err := w.Write([]byte(htmlParser)); err != nil {
-			fmt.Println(err)
+		slugs[slug] = true
+
+		built = append(built, builtDocument{
+			link:    documentLink{Name: document.Name, URL: route + core.IfSlashPrefixString(slug) + "/openapi.json"},
+			jsonDoc: jsonDoc,
+			yamlDoc: yamlDoc,
+		})
+	}
+	if len(built) == 0 {
+		return
+	}
+
+	links := make([]documentLink, 0, len(built))
+	for _, document := range built {
+		serveDocument(app, document.link.URL, document.jsonDoc, document.yamlDoc, config)
+		links = append(links, document.link)
+	}
+	mountUI(route, app, links, config)
+}
+
+// documentSlug returns the path segment of a document name: lowercased, with
+// spaces replaced with dashes and other characters than a-z, 0-9 and dashes
+// removed. Names without any of them give "document".
+func documentSlug(name string) string {
+	slug := strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= '0' && r <= '9', r == '-':
+			return r
+		case r == ' ':
+			return '-'
+		default:
+			return -1
+		}
+	}, strings.ToLower(strings.TrimSpace(name)))
+	if slug == "" {
+		return "document"
+	}
+	return slug
+}
+
+// buildDocument parses the app routes into spec, and encodes the document as
+// JSON and YAML.
+func buildDocument(app *core.App, spec *SpecBuilder) ([]byte, []byte, error) {
+	spec.ParsePaths(app)
+	jsonDoc, err := spec.Document()
+	if err != nil {
+		return nil, nil, err
+	}
+	yamlDoc, err := jsonToYAML(jsonDoc)
+	if err != nil {
+		return nil, nil, err
+	}
+	return jsonDoc, yamlDoc, nil
+}
+
+// serveDocument serves the document as JSON at documentPath and as YAML next
+// to it.
+func serveDocument(app *core.App, documentPath string, jsonDoc []byte, yamlDoc []byte, config Config) {
+
+	// Serve the OpenAPI document as JSON, or YAML when the client asks for it
+	config.handle(app, documentPath, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Vary", "Accept")
+		if acceptsYAML(r) {
+			writeDocument(w, "application/yaml", yamlDoc)
+			return
 		}
+		writeDocument(w, "application/json", jsonDoc)
 	}))
+
+	// Serve the OpenAPI document as YAML
+	config.handle(app, strings.TrimSuffix(documentPath, ".json")+".yaml", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		writeDocument(w, "application/yaml", yamlDoc)
+	}))
+}
+
+// Document returns the JSON document as served by SetUp, loaded and encoded
+// again with kin-openapi. ParsePaths must be called first.
+func (spec *SpecBuilder) Document() ([]byte, error) {
+	jsonBytes, err := json.Marshal(spec)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx := context.Background()
+	loader := &openapi3.Loader{Context: ctx, IsExternalRefsAllowed: true}
+	doc, err := loader.LoadFromData(jsonBytes)
+	if err != nil {
+		return nil, err
+	}
+	// Validate document
+	_ = doc.Validate(ctx)
+
+	return json.Marshal(doc)
+}
+
+func writeDocument(w http.ResponseWriter, contentType string, data []byte) {
+	w.Header().Set("Content-Type", contentType)
+	if _, err := w.Write(data); err != nil {
+		fmt.Println(err)
+	}
 }
@@ -1,9 +1,12 @@
 package swagger_test
 
 import (
+	"encoding/json"
+	"reflect"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"github.com/tinh-tinh/swagger/v2"
 	"github.com/tinh-tinh/tinhtinh/v2/core"
 )
@@ -65,8 +68,8 @@ func Test_Spec(t *testing.T) {
 	assert.Empty(t, paths["/api/auth"].Post.OperationID)
 	assert.Empty(t, paths["/api/auth"].Post.Consumes)
 	assert.Empty(t, paths["/api/auth"].Post.Produces)
-	assert.NotNil(t, paths["/api/auth"].Post.RequestBody.Content["SignUpUser"])
-	assert.Equal(t, "#/components/schemas/SignUpUser", paths["/api/auth"].Post.RequestBody.Content["SignUpUser"].Schema.Ref)
+	assert.NotNil(t, paths["/api/auth"].Post.RequestBody.Content["application/json"])
+	assert.Equal(t, "#/components/schemas/SignUpUser", paths["/api/auth"].Post.RequestBody.Content["application/json"].Schema.Ref)
 	assert.Empty(t, paths["/api/auth"].Post.Schemes)
 	assert.False(t, paths["/api/auth"].Post.Deprecated)
 	assert.Empty(t, paths["/api/auth"].Post.Security)
@@ -83,7 +86,8 @@ func Test_Spec(t *testing.T) {
 	assert.Equal(t, "query", paths["/api/users"].Get.Parameters[0].In)
 	assert.Equal(t, "name", paths["/api/users"].Get.Parameters[0].Name)
 	assert.Equal(t, "string", paths["/api/users"].Get.Parameters[0].Schema.Type)
-	assert.Equal(t, "ac", paths["/api/users"].Get.Parameters[0].Default)
+	assert.Equal(t, "ac", paths["/api/users"].Get.Parameters[0].Example)
+	assert.Empty(t, paths["/api/users"].Get.Parameters[0].Default)
 	assert.Equal(t, "age", paths["/api/users"].Get.Parameters[1].Name)
 	assert.Equal(t, "integer", paths["/api/users"].Get.Parameters[1].Schema.Type)
 
@@ -100,3 +104,261 @@ func Test_Spec(t *testing.T) {
 	assert.NotNil(t, paths["/api/posts/{id}"].Get.Responses["200"].Content["application/json"].Schema)
 	assert.Equal(t, "#/components/schemas/Response", paths["/api/posts/{id}"].Get.Responses["200"].Content["application/json"].Schema.Ref)
 }
+
+func Test_TopLevelResponses(t *testing.T) {
+	type User struct {
+		Name string `json:"name" example:"John"`
+	}
+
+	controller := func(module core.Module) core.Controller {
+		ctrl := module.NewController("Users").Registry()
+
+		ctrl.Metadata(swagger.ApiOkResponse([]User{})).Get("", func(ctx core.Ctx) error {
+			return ctx.JSON(core.Map{"data": "ok"})
+		})
+
+		ctrl.Metadata(swagger.ApiOkResponse("")).Get("name", func(ctx core.Ctx) error {
+			return ctx.JSON(core.Map{"data": "ok"})
+		})
+
+		ctrl.Metadata(swagger.ApiOkResponse(map[string]int{})).Get("count", func(ctx core.Ctx) error {
+			return ctx.JSON(core.Map{"data": "ok"})
+		})
+
+		return ctrl
+	}
+
+	appModule := func() core.Module {
+		return core.NewModule(core.NewModuleOptions{
+			Controllers: []core.Controllers{controller},
+		})
+	}
+
+	document := swagger.NewSpecBuilder()
+	document.ParsePaths(core.CreateFactory(appModule))
+
+	list := document.Paths["/users"].Get.Responses["200"].Content["application/json"].Schema
+	assert.Equal(t, "array", list.Type)
+	assert.Empty(t, list.Ref)
+	assert.Equal(t, "#/components/schemas/User", list.Items.Ref)
+	assert.NotNil(t, document.Components.Schemas["User"])
+
+	name := document.Paths["/users/name"].Get.Responses["200"].Content["application/json"].Schema
+	assert.Equal(t, "string", name.Type)
+	assert.Empty(t, name.Ref)
+
+	count := document.Paths["/users/count"].Get.Responses["200"].Content["application/json"].Schema
+	assert.Equal(t, "object", count.Type)
+	assert.Equal(t, "integer", count.AdditionalProperties.Type)
+
+	assert.Len(t, document.Components.Schemas, 1)
+}
+
+func Test_Examples(t *testing.T) {
+	type CreateUser struct {
+		Name string `json:"name"`
+	}
+	type User struct {
+		ID   int    `json:"id"`
+		Name string `json:"name"`
+	}
+
+	controller := func(module core.Module) core.Controller {
+		ctrl := module.NewController("Users").Registry()
+
+		ctrl.Metadata(
+			swagger.ApiBodyExample("minimal", CreateUser{Name: "John"}),
+			swagger.ApiOkResponse(&User{}),
+			swagger.ApiOkResponseExample("created", User{ID: 1, Name: "John"}),
+			swagger.ApiOkResponseExample("invalid", func() {}),
+		).Pipe(core.BodyParser[CreateUser]{}).Post("", func(ctx core.Ctx) error {
+			return ctx.JSON(core.Map{"data": "ok"})
+		})
+
+		return ctrl
+	}
+
+	appModule := func() core.Module {
+		return core.NewModule(core.NewModuleOptions{
+			Controllers: []core.Controllers{controller},
+		})
+	}
+
+	document := swagger.NewSpecBuilder().SetEncoder(func(v interface{}) ([]byte, error) {
+		if user, ok := v.(User); ok {
+			return json.Marshal(map[string]interface{}{"data": user})
+		}
+		return json.Marshal(v)
+	})
+	document.ParsePaths(core.CreateFactory(appModule))
+
+	operation := document.Paths["/users"].Post
+	body := operation.RequestBody.Content["application/json"].Examples["minimal"]
+	require.NotNil(t, body)
+	assert.Equal(t, "minimal", body.Summary)
+	assert.Equal(t, json.RawMessage(`{"name":"John"}`), body.Value)
+
+	response := operation.Responses["200"].Content["application/json"]
+	assert.Equal(t, "#/components/schemas/User", response.Schema.Ref)
+	assert.Equal(t, json.RawMessage(`{"data":{"id":1,"name":"John"}}`), response.Examples["created"].Value)
+	assert.Nil(t, response.Examples["invalid"])
+
+	require.Len(t, document.Diagnostics, 1)
+	assert.Equal(t, swagger.DiagnosticExampleEncoding, document.Diagnostics[0].Kind)
+	assert.Equal(t, "POST /users", document.Diagnostics[0].Route)
+
+	// The encoder of the app is used by default
+	app := core.CreateFactory(appModule, core.AppOptions{Encoder: func(v interface{}) ([]byte, error) {
+		return json.Marshal(map[string]interface{}{"payload": v})
+	}})
+	document = swagger.NewSpecBuilder()
+	document.ParsePaths(app)
+	body = document.Paths["/users"].Post.RequestBody.Content["application/json"].Examples["minimal"]
+	assert.Equal(t, json.RawMessage(`{"payload":{"name":"John"}}`), body.Value)
+
+	// Apps without a readable encoder fall back to encoding/json
+	assert.Nil(t, swagger.ReadEncoder(reflect.ValueOf(&struct{ encoder string }{}).Elem()))
+}
+
+func Test_MetadataPrecedence(t *testing.T) {
+	controller := func(module core.Module) core.Controller {
+		ctrl := module.NewController("Users").Metadata(
+			swagger.ApiTag("Users"),
+			swagger.ApiDeprecated(),
+		).Registry()
+
+		ctrl.Get("", func(ctx core.Ctx) error {
+			return ctx.JSON(core.Map{"data": "ok"})
+		})
+
+		ctrl.Metadata(
+			swagger.ApiTag("Admin"),
+			swagger.ApiSecurity("apiKey"),
+			swagger.ApiDeprecated(false),
+		).Delete("{id}", func(ctx core.Ctx) error {
+			return ctx.JSON(core.Map{"data": "ok"})
+		})
+
+		return ctrl
+	}
+
+	userModule := func(module core.Module) core.Module {
+		return swagger.ModuleMetadata(module.New(core.NewModuleOptions{
+			Controllers: []core.Controllers{controller},
+		}), swagger.ApiTag("Module"), swagger.ApiSecurity("bearerAuth"))
+	}
+
+	appModule := func() core.Module {
+		return core.NewModule(core.NewModuleOptions{
+			Imports: []core.Modules{userModule},
+		})
+	}
+
+	document := swagger.NewSpecBuilder()
+	document.ParsePaths(core.CreateFactory(appModule))
+
+	list := document.Paths["/users"].Get
+	assert.Equal(t, []string{"Users"}, list.Tags)
+	assert.True(t, list.Deprecated)
+	assert.Equal(t, []map[string][]string{{"bearerAuth": {}}}, list.Security)
+
+	remove := document.Paths["/users/{id}"].Delete
+	assert.Equal(t, []string{"Admin"}, remove.Tags)
+	assert.False(t, remove.Deprecated)
+	assert.Equal(t, []map[string][]string{{"apiKey": {}}}, remove.Security)
+}
+
+func Test_GlobalTags(t *testing.T) {
+	server := core.CreateFactory(AppModule)
+	server.SetGlobalPrefix("api")
+
+	document := swagger.NewSpecBuilder().
+		AddTag("User", "Manage users", &swagger.ExternalDocsObject{
+			Url: "https://example.com/users",
+		}).
+		AddTag("Internal", "Not used by any route").
+		SetTagOrder("Auth", "User").
+		AddTagGroup("Accounts", "Auth", "User")
+
+	document.ParsePaths(server)
+
+	names := []string{}
+	for _, tag := range document.Tags {
+		names = append(names, tag.Name)
+	}
+	assert.Equal(t, []string{"Auth", "User", "Internal", "Post"}, names)
+	assert.Equal(t, "Manage users", document.Tags[1].Description)
+	assert.Equal(t, "https://example.com/users", document.Tags[1].ExternalDocs.Url)
+	assert.Empty(t, document.Tags[0].Description)
+
+	data, err := json.Marshal(document)
+	require.Nil(t, err)
+	assert.Contains(t, string(data), `"x-tagGroups":[{"name":"Accounts","tags":["Auth","User"]}]`)
+
+	// Tags of operations which are no longer documented are dropped, the
+	// declared ones are kept
+	document.Filter(swagger.ByTag("User")).ParsePaths(server)
+	names = []string{}
+	for _, tag := range document.Tags {
+		names = append(names, tag.Name)
+	}
+	assert.Equal(t, []string{"User", "Internal"}, names)
+}
+
+type Chargeback struct {
+	Amount int `json:"amount"`
+}
+
+func (Chargeback) ExternalDocs() *swagger.ExternalDocsObject {
+	return &swagger.ExternalDocsObject{Url: "https://example.com/chargebacks", Description: "Chargeback policy"}
+}
+
+func Test_ExternalDocs(t *testing.T) {
+	type Refund struct {
+		Reason string `json:"reason" externalDocs:"https://example.com/refunds#reasons, Accepted reasons"`
+	}
+
+	controller := func(module core.Module) core.Controller {
+		ctrl := module.NewController("Refunds").Registry()
+
+		ctrl.Metadata(
+			swagger.ApiExternalDocs("https://example.com/runbooks/refunds", "Refund runbook"),
+		).Pipe(core.BodyParser[Refund]{}).Post("", func(ctx core.Ctx) error {
+			return ctx.JSON(core.Map{"data": "ok"})
+		})
+
+		ctrl.Metadata(swagger.ApiOkResponse(Chargeback{})).Get("chargebacks", func(ctx core.Ctx) error {
+			return ctx.JSON(core.Map{"data": "ok"})
+		})
+
+		return ctrl
+	}
+
+	appModule := func() core.Module {
+		return core.NewModule(core.NewModuleOptions{
+			Controllers: []core.Controllers{controller},
+		})
+	}
+
+	document := swagger.NewSpecBuilder().
+		SetExternalDocs("https://example.com/design", "Design docs")
+	document.ParsePaths(core.CreateFactory(appModule))
+
+	assert.Equal(t, &swagger.ExternalDocsObject{
+		Url:         "https://example.com/design",
+		Description: "Design docs",
+	}, document.ExternalDocs)
+	assert.Equal(t, &swagger.ExternalDocsObject{
+		Url:         "https://example.com/runbooks/refunds",
+		Description: "Refund runbook",
+	}, document.Paths["/refunds"].Post.ExternalDocs)
+	assert.Equal(t, &swagger.ExternalDocsObject{
+		Url:         "https://example.com/refunds#reasons",
+		Description: "Accepted reasons",
+	}, document.Components.Schemas["Refund"].Properties["reason"].ExternalDocs)
+	assert.Nil(t, document.Components.Schemas["Refund"].ExternalDocs)
+	assert.Equal(t, &swagger.ExternalDocsObject{
+		Url:         "https://example.com/chargebacks",
+		Description: "Chargeback policy",
+	}, document.Components.Schemas["Chargeback"].ExternalDocs)
+}
@@ -0,0 +1,208 @@
+// Package swaggertest compares the OpenAPI document of a tinhtinh app with a
+// golden file committed next to its tests:
+//
+//	func TestOpenAPI(t *testing.T) {
+//		spec := swaggertest.Build(t, app.AppModule, swaggertest.Options{Prefix: "api"})
+//		swaggertest.AssertGolden(t, spec, "testdata/openapi.golden.json")
+//	}
+//
+// Run the tests with -update-golden, or with SWAGGERTEST_UPDATE=1, to write
+// the golden files from the current documents.
+package swaggertest
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/pmezard/go-difflib/difflib"
+	"github.com/tinh-tinh/swagger/v2"
+	"github.com/tinh-tinh/tinhtinh/v2/core"
+)
+
+var update = flag.Bool("update-golden", false, "update the golden files of swaggertest")
+
+// DefaultStrip lists the fields removed by Normalize when no field is given,
+// which change between builds without changing the API.
+var DefaultStrip = []string{"servers"}
+
+// PlaceholderVersion replaces info.version in normalized documents. The
+// version changes between builds without changing the API, but OpenAPI
+// requires it, so golden files keep a fixed one to stay valid documents.
+const PlaceholderVersion = "0.0.0"
+
+type Options struct {
+	// Prefix is the global prefix of the app, as given to SetGlobalPrefix.
+	Prefix string
+	// Configure applies the rest of the app setup affecting the document,
+	// e.g. EnableVersioning.
+	Configure func(app *core.App)
+	// Spec is the builder to fill, swagger.NewSpecBuilder() by default.
+	Spec *swagger.SpecBuilder
+	// Strip lists the dotted paths of the fields removed from the document
+	// before comparison, DefaultStrip by default.
+	Strip []string
+}
+
+func optionsOf(opts []Options) Options {
+	if len(opts) > 0 {
+		return opts[0]
+	}
+	return Options{}
+}
+
+// Build creates the app of module without listening and parses its routes
+// into a spec.
+func Build(t testing.TB, module core.ModuleParam, opts ...Options) *swagger.SpecBuilder {
+	t.Helper()
+	opt := optionsOf(opts)
+
+	app := core.CreateFactory(module)
+	if opt.Prefix != "" {
+		app.SetGlobalPrefix(opt.Prefix)
+	}
+	if opt.Configure != nil {
+		opt.Configure(app)
+	}
+
+	spec := opt.Spec
+	if spec == nil {
+		spec = swagger.NewSpecBuilder()
+	}
+	spec.ParsePaths(app)
+	return spec
+}
+
+// Document returns the normalized JSON document of spec, as served by
+// swagger.SetUp.
+func Document(t testing.TB, spec *swagger.SpecBuilder, strip ...string) []byte {
+	t.Helper()
+
+	data, err := spec.Document()
+	if err != nil {
+		t.Fatalf("swaggertest: building document: %v", err)
+	}
+	data, err = Normalize(data, strip...)
+	if err != nil {
+		t.Fatalf("swaggertest: %v", err)
+	}
+	return data
+}
+
+// Normalize removes the fields at the given dotted paths from a JSON
+// document, DefaultStrip when none is given, replaces its info.version with
+// PlaceholderVersion, and indents it with sorted keys, so that documents can
+// be compared line by line.
+func Normalize(data []byte, strip ...string) ([]byte, error) {
+	if len(strip) == 0 {
+		strip = DefaultStrip
+	}
+
+	var doc any
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	if err := decoder.Decode(&doc); err != nil {
+		return nil, err
+	}
+	for _, path := range strip {
+		remove(doc, strings.Split(path, "."))
+	}
+	if root, ok := doc.(map[string]any); ok {
+		if info, ok := root["info"].(map[string]any); ok {
+			if _, ok := info["version"]; ok {
+				info["version"] = PlaceholderVersion
+			}
+		}
+	}
+
+	// encoding/json sorts the keys of maps.
+	out, err := json.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append(out, '\n'), nil
+}
+
+func remove(value any, path []string) {
+	obj, ok := value.(map[string]any)
+	if !ok || len(path) == 0 {
+		return
+	}
+	if len(path) == 1 {
+		delete(obj, path[0])
+		return
+	}
+	remove(obj[path[0]], path[1:])
+}
+
+// AssertGolden compares the normalized document of spec with the golden file
+// at path, and reports the changes and a line diff when they differ. With
+// -update-golden or SWAGGERTEST_UPDATE=1, it writes the golden file instead.
+func AssertGolden(t testing.TB, spec *swagger.SpecBuilder, path string, opts ...Options) {
+	t.Helper()
+	actual := Document(t, spec, optionsOf(opts).Strip...)
+
+	if updating() {
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatalf("swaggertest: %v", err)
+		}
+		if err := os.WriteFile(path, actual, 0o644); err != nil {
+			t.Fatalf("swaggertest: %v", err)
+		}
+		return
+	}
+
+	golden, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		t.Errorf("swaggertest: golden file %s does not exist, run the test with -update-golden to create it", path)
+		return
+	}
+	if err != nil {
+		t.Fatalf("swaggertest: %v", err)
+	}
+	if bytes.Equal(golden, actual) {
+		return
+	}
+
+	var report strings.Builder
+	report.WriteString("swaggertest: document differs from golden file " + path + "\n")
+	if changes, err := swagger.CompareDocuments(golden, actual); err == nil && len(changes) > 0 {
+		report.WriteString("\nChanges:\n")
+		for _, change := range changes {
+			report.WriteString("  " + change.String() + "\n")
+		}
+	}
+	diff, _ := difflib.GetUnifiedDiffString(difflib.UnifiedDiff{
+		A:        difflib.SplitLines(string(golden)),
+		B:        difflib.SplitLines(string(actual)),
+		FromFile: path,
+		ToFile:   "generated",
+		Context:  3,
+	})
+	report.WriteString("\n" + diff)
+	report.WriteString("\nRun the test with -update-golden to accept the changes.")
+	t.Error(report.String())
+}
+
+// AssertModule builds the spec of module and compares it with the golden
+// file at path.
+func AssertModule(t testing.TB, module core.ModuleParam, path string, opts ...Options) {
+	t.Helper()
+	AssertGolden(t, Build(t, module, opts...), path, opts...)
+}
+
+func updating() bool {
+	if *update {
+		return true
+	}
+	switch os.Getenv("SWAGGERTEST_UPDATE") {
+	case "", "0", "false":
+		return false
+	default:
+		return true
+	}
+}
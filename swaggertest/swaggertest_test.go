@@ -0,0 +1,104 @@
+package swaggertest_test
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/tinh-tinh/swagger/v2"
+	"github.com/tinh-tinh/swagger/v2/swaggertest"
+	"github.com/tinh-tinh/tinhtinh/v2/core"
+)
+
+type User struct {
+	Name  string `json:"name" validate:"required"`
+	Email string `json:"email"`
+}
+
+func userController(module core.Module) core.Controller {
+	ctrl := module.NewController("Users").Metadata(swagger.ApiTag("User")).Registry()
+
+	ctrl.Metadata(swagger.ApiOkResponse([]User{})).Get("", func(ctx core.Ctx) error {
+		return ctx.JSON(core.Map{"data": []User{}})
+	})
+	ctrl.Pipe(core.BodyParser[User]{}).Post("", func(ctx core.Ctx) error {
+		return ctx.JSON(core.Map{"data": ctx.Body()})
+	})
+
+	return ctrl
+}
+
+func appModule() core.Module {
+	return core.NewModule(core.NewModuleOptions{
+		Controllers: []core.Controllers{userController},
+	})
+}
+
+// recorder records the errors reported by the assertions.
+type recorder struct {
+	testing.TB
+	errors []string
+}
+
+func (r *recorder) Error(args ...any) {
+	r.errors = append(r.errors, fmt.Sprint(args...))
+}
+
+func (r *recorder) Errorf(format string, args ...any) {
+	r.errors = append(r.errors, fmt.Sprintf(format, args...))
+}
+
+func Test_AssertModule(t *testing.T) {
+	swaggertest.AssertModule(t, appModule, "testdata/users.golden.json", swaggertest.Options{Prefix: "api"})
+
+	// Golden files stay valid documents
+	changes, err := swagger.CompareFiles("testdata/users.golden.json", "testdata/users.golden.json")
+	require.Nil(t, err)
+	require.Empty(t, changes)
+}
+
+func Test_AssertGolden(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "openapi.golden.json")
+
+	r := &recorder{TB: t}
+	swaggertest.AssertModule(r, appModule, path)
+	require.Len(t, r.errors, 1)
+	require.Contains(t, r.errors[0], "does not exist")
+
+	t.Setenv("SWAGGERTEST_UPDATE", "1")
+	spec := swaggertest.Build(t, appModule, swaggertest.Options{Prefix: "api"})
+	swaggertest.AssertGolden(t, spec, path)
+	_, err := os.Stat(path)
+	require.Nil(t, err)
+
+	// The version is normalized, the path is not
+	t.Setenv("SWAGGERTEST_UPDATE", "")
+	spec.SetVersion("2.0")
+	swaggertest.AssertGolden(t, spec, path)
+
+	delete(spec.Paths, "/api/users")
+	r = &recorder{TB: t}
+	swaggertest.AssertGolden(r, spec, path)
+	require.Len(t, r.errors, 1)
+	require.Contains(t, r.errors[0], "breaking: GET /api/users: endpoint removed")
+	require.Contains(t, r.errors[0], "--- "+path)
+	require.Contains(t, r.errors[0], `-    "/api/users": {`)
+}
+
+func Test_Normalize(t *testing.T) {
+	data, err := swaggertest.Normalize([]byte(`{"servers":[{"url":"http://localhost"}],"info":{"version":"1.2.3","title":"Shop"},"openapi":"3.0.0"}`))
+	require.Nil(t, err)
+	require.Equal(t, "{\n  \"info\": {\n    \"title\": \"Shop\",\n    \"version\": \"0.0.0\"\n  },\n  \"openapi\": \"3.0.0\"\n}\n", string(data))
+
+	data, err = swaggertest.Normalize([]byte(`{"info":{"version":"1.2.3","title":"Shop"},"x":{"big":12345678901234567890}}`), "info.title", "x.missing.field")
+	require.Nil(t, err)
+	require.Equal(t, "{\n  \"info\": {\n    \"version\": \"0.0.0\"\n  },\n  \"x\": {\n    \"big\": 12345678901234567890\n  }\n}\n", string(data))
+
+	_, err = swaggertest.Normalize([]byte(`[]`))
+	require.Nil(t, err)
+
+	_, err = swaggertest.Normalize([]byte(`{`))
+	require.NotNil(t, err)
+}
@@ -0,0 +1,184 @@
+package swagger
+
+import (
+	"encoding/json"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// fieldTagKeys are the struct tags documenting a field. Each of them can also
+// be written as a key=value pair of the compact openapi tag, e.g.
+//
+//	Age int `openapi:"description=Age of the user;minimum=0;maximum=150"`
+//
+// Pairs are separated by semicolons so that descriptions and patterns may
+// contain commas. A key without value, such as readOnly, is set to true.
+// The externalDocs tag holds an url optionally followed by a comma and a
+// description.
+// Dedicated tags take precedence over the openapi tag.
+var fieldTagKeys = []string{
+	"title",
+	"description",
+	"format",
+	"default",
+	"minimum",
+	"maximum",
+	"pattern",
+	"readOnly",
+	"writeOnly",
+	"deprecated",
+	"nullable",
+	"externalDocs",
+}
+
+// ExternalDocumenter is implemented by DTOs linking their schema to external
+// documentation, which struct tags can only do for their fields:
+//
+//	func (Refund) ExternalDocs() *swagger.ExternalDocsObject {
+//		return &swagger.ExternalDocsObject{Url: "https://example.com/refunds", Description: "Refund policy"}
+//	}
+type ExternalDocumenter interface {
+	ExternalDocs() *ExternalDocsObject
+}
+
+// parseFieldTags collects the documentation tags of a struct field.
+func parseFieldTags(field reflect.StructField) map[string]string {
+	opts := make(map[string]string)
+	for _, part := range strings.Split(field.Tag.Get("openapi"), ";") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		key, value, found := strings.Cut(part, "=")
+		if !found {
+			value = "true"
+		}
+		opts[strings.TrimSpace(key)] = strings.TrimSpace(value)
+	}
+	for _, key := range fieldTagKeys {
+		if value, ok := field.Tag.Lookup(key); ok {
+			opts[key] = value
+		}
+	}
+	return opts
+}
+
+// applyFieldTags sets the documentation tags of a field of type t on its
+// schema. Malformed numbers and booleans are ignored.
+func applyFieldTags(schema *SchemaObject, opts map[string]string, t reflect.Type) {
+	for key, value := range opts {
+		switch key {
+		case "title":
+			schema.Title = value
+		case "description":
+			schema.Description = value
+		case "format":
+			schema.Format = value
+		case "pattern":
+			schema.Pattern = value
+		case "default":
+			schema.Default = parseValue(value, t)
+		case "minimum":
+			if min, err := strconv.ParseFloat(value, 64); err == nil {
+				schema.Minimum = &min
+			}
+		case "maximum":
+			if max, err := strconv.ParseFloat(value, 64); err == nil {
+				schema.Maximum = &max
+			}
+		case "readOnly":
+			schema.ReadOnly, _ = strconv.ParseBool(value)
+		case "writeOnly":
+			schema.WriteOnly, _ = strconv.ParseBool(value)
+		case "deprecated":
+			schema.Deprecated, _ = strconv.ParseBool(value)
+		case "nullable":
+			schema.Nullable, _ = strconv.ParseBool(value)
+		case "externalDocs":
+			url, description, _ := strings.Cut(value, ",")
+			schema.ExternalDocs = &ExternalDocsObject{
+				Url:         strings.TrimSpace(url),
+				Description: strings.TrimSpace(description),
+			}
+		}
+	}
+}
+
+// parseValue converts a raw tag value into a value of type t. Slices accept
+// either a JSON array or a comma separated list, structs and maps a JSON
+// object. The raw string is returned when it cannot be converted.
+func parseValue(raw string, t reflect.Type) any {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if isTimeType(t) {
+		return raw
+	}
+
+	switch t.Kind() {
+	case reflect.Bool:
+		if v, err := strconv.ParseBool(raw); err == nil {
+			return v
+		}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		if v, err := strconv.ParseInt(raw, 10, 64); err == nil {
+			return v
+		}
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		if v, err := strconv.ParseUint(raw, 10, 64); err == nil {
+			return v
+		}
+	case reflect.Float32, reflect.Float64:
+		if v, err := strconv.ParseFloat(raw, 64); err == nil {
+			return v
+		}
+	case reflect.Slice, reflect.Array:
+		if strings.HasPrefix(strings.TrimSpace(raw), "[") {
+			var v []any
+			if err := json.Unmarshal([]byte(raw), &v); err == nil {
+				return v
+			}
+			return raw
+		}
+		values := []any{}
+		for _, elem := range strings.Split(raw, ",") {
+			values = append(values, parseValue(strings.TrimSpace(elem), t.Elem()))
+		}
+		return values
+	case reflect.Struct, reflect.Map, reflect.Interface:
+		var v any
+		if err := json.Unmarshal([]byte(raw), &v); err == nil {
+			return v
+		}
+	}
+	return raw
+}
+
+type namedExample struct {
+	Name  string
+	Value any
+}
+
+// parseExamples parses the examples tag, a semicolon separated list of
+// name=value pairs such as
+//
+//	Status string `query:"status" examples:"active=ACTIVE;banned=BANNED"`
+//
+// Values are converted to type t like the example tag. The pairs are returned
+// in declaration order.
+func parseExamples(raw string, t reflect.Type) []namedExample {
+	var examples []namedExample
+	for _, part := range strings.Split(raw, ";") {
+		name, value, found := strings.Cut(part, "=")
+		name = strings.TrimSpace(name)
+		if !found || name == "" {
+			continue
+		}
+		examples = append(examples, namedExample{
+			Name:  name,
+			Value: parseValue(strings.TrimSpace(value), t),
+		})
+	}
+	return examples
+}
@@ -0,0 +1,107 @@
+package swagger
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/tinh-tinh/tinhtinh/v2/core"
+)
+
+func Test_FieldTags(t *testing.T) {
+	type User struct {
+		ID       string  `json:"id" readOnly:"true" format:"uuid"`
+		Name     string  `json:"name" title:"Name" description:"Full name, as displayed" pattern:"^[a-zA-Z ]{1,64}$"`
+		Age      int     `json:"age" openapi:"description=Age of the user;minimum=0;maximum=150;default=18"`
+		Password string  `json:"password" openapi:"writeOnly;format=password"`
+		Nickname *string `json:"nickname" openapi:"nullable;deprecated" description:"Overrides the compact tag"`
+	}
+
+	schema := ParseSchema(&User{})
+	asrt := assert.New(t)
+
+	asrt.True(schema.Properties["id"].ReadOnly)
+	asrt.Equal("uuid", schema.Properties["id"].Format)
+	asrt.Equal("Name", schema.Properties["name"].Title)
+	asrt.Equal("Full name, as displayed", schema.Properties["name"].Description)
+	asrt.Equal("^[a-zA-Z ]{1,64}$", schema.Properties["name"].Pattern)
+	asrt.Equal("Age of the user", schema.Properties["age"].Description)
+	asrt.Equal(float64(0), *schema.Properties["age"].Minimum)
+	asrt.Equal(float64(150), *schema.Properties["age"].Maximum)
+	asrt.Equal(int64(18), schema.Properties["age"].Default)
+	asrt.True(schema.Properties["password"].WriteOnly)
+	asrt.Equal("password", schema.Properties["password"].Format)
+	asrt.True(schema.Properties["nickname"].Nullable)
+	asrt.True(schema.Properties["nickname"].Deprecated)
+	asrt.Equal("Overrides the compact tag", schema.Properties["nickname"].Description)
+
+	type Filter struct {
+		Page int    `query:"page" description:"Page number" minimum:"1" default:"1"`
+		Sort string `query:"sort" openapi:"deprecated;pattern=^(asc|desc)$"`
+	}
+
+	params := ScanQuery(&Filter{}, core.InQuery)
+	asrt.Equal("Page number", params[0].Description)
+	asrt.Empty(params[0].Schema.Description)
+	asrt.Equal(float64(1), *params[0].Schema.Minimum)
+	asrt.Equal(int64(1), params[0].Schema.Default)
+	asrt.True(params[1].Deprecated)
+	asrt.Equal("^(asc|desc)$", params[1].Schema.Pattern)
+}
+
+func Test_ParseValue(t *testing.T) {
+	asrt := assert.New(t)
+	asrt.Equal(true, parseValue("true", reflect.TypeOf(false)))
+	asrt.Equal(int64(-3), parseValue("-3", reflect.TypeOf(0)))
+	asrt.Equal(uint64(3), parseValue("3", reflect.TypeOf(uint(0))))
+	asrt.Equal(1.5, parseValue("1.5", reflect.TypeOf(float32(0))))
+	asrt.Equal("abc", parseValue("abc", reflect.TypeOf("")))
+	asrt.Equal("abc", parseValue("abc", reflect.TypeOf(0)))
+	asrt.Equal([]any{int64(1), int64(2)}, parseValue("1, 2", reflect.TypeOf([]int{})))
+	asrt.Equal([]any{"a", float64(1)}, parseValue(`["a", 1]`, reflect.TypeOf([]any{})))
+	asrt.Equal(map[string]any{"a": float64(1)}, parseValue(`{"a": 1}`, reflect.TypeOf(map[string]int{})))
+}
+
+func Test_TypedExamples(t *testing.T) {
+	type Address struct {
+		City string `json:"city"`
+	}
+	type User struct {
+		Age     int       `json:"age" example:"42"`
+		Active  bool      `json:"active" example:"true"`
+		Tags    []string  `json:"tags" example:"a,b"`
+		Scores  []float64 `json:"scores" example:"[1.5, 2]"`
+		Address *Address  `json:"address" validate:"nested" example:"{\"city\":\"Hanoi\"}"`
+		Role    string    `json:"role" examples:"admin=ADMIN;guest=GUEST"`
+	}
+
+	schema := ParseSchema(&User{})
+	asrt := assert.New(t)
+	asrt.Equal(int64(42), schema.Properties["age"].Example)
+	asrt.Equal(true, schema.Properties["active"].Example)
+	asrt.Equal([]any{"a", "b"}, schema.Properties["tags"].Example)
+	asrt.Equal([]any{1.5, float64(2)}, schema.Properties["scores"].Example)
+	asrt.Equal(map[string]any{"city": "Hanoi"}, schema.Properties["address"].Example)
+	asrt.NotNil(schema.Properties["address"].Properties["city"])
+	asrt.Equal("ADMIN", schema.Properties["role"].Example)
+
+	type Filter struct {
+		Limit  int    `query:"limit" example:"20" default:"10"`
+		Status string `query:"status" examples:"active=ACTIVE;banned=BANNED"`
+		Sort   string `query:"sort" example:"name" examples:"name=name;date=-created_at"`
+	}
+
+	params := ScanQuery(&Filter{}, core.InQuery)
+	asrt.Equal(int64(20), params[0].Example)
+	asrt.Equal(int64(10), params[0].Schema.Default)
+	asrt.Empty(params[0].Default)
+	asrt.Nil(params[1].Example)
+	asrt.Len(params[1].Examples, 2)
+	asrt.Equal("ACTIVE", params[1].Examples["active"].Value)
+	asrt.Equal("BANNED", params[1].Examples["banned"].Value)
+
+	// OpenAPI forbids example next to examples
+	asrt.Nil(params[2].Example)
+	asrt.Len(params[2].Examples, 2)
+	asrt.Equal("-created_at", params[2].Examples["date"].Value)
+}
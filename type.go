@@ -1,5 +1,11 @@
 package swagger
 
+import (
+	"net/http"
+
+	"github.com/tinh-tinh/tinhtinh/v2/core"
+)
+
 // -------- Info Object --------
 type InfoObject struct {
 	Title          string             `json:"title"` // required
@@ -34,31 +40,55 @@ type PathItemObject struct {
 	Delete *OperationObject `json:"delete,omitempty"`
 }
 
+// operations returns the operations of the path item in a stable order.
+func (item *PathItemObject) operations() []*OperationObject {
+	var operations []*OperationObject
+	for _, op := range []*OperationObject{item.Get, item.Post, item.Put, item.Patch, item.Delete} {
+		if op != nil {
+			operations = append(operations, op)
+		}
+	}
+	return operations
+}
+
 // -------- Operation Object --------
 type OperationObject struct {
-	Tags        []string                   `json:"tags,omitempty"`
-	Summary     string                     `json:"summary,omitempty"`
-	Description string                     `json:"description,omitempty"`
-	OperationID string                     `json:"operationId,omitempty"` // camelCase fix
-	Consumes    []string                   `json:"consumes,omitempty"`    // technically OpenAPI 2.0, not 3.0
-	Produces    []string                   `json:"produces,omitempty"`    // technically OpenAPI 2.0
-	Parameters  []*ParameterObject         `json:"parameters,omitempty"`
-	RequestBody *RequestBodyObject         `json:"requestBody,omitempty"`
-	Schemes     []string                   `json:"schemes,omitempty"` // OpenAPI 2.0 field, not 3.0
-	Deprecated  bool                       `json:"deprecated,omitempty"`
-	Security    []map[string][]string      `json:"security,omitempty"`
-	Responses   map[string]*ResponseObject `json:"responses"` // required
+	Tags         []string                   `json:"tags,omitempty"`
+	Summary      string                     `json:"summary,omitempty"`
+	Description  string                     `json:"description,omitempty"`
+	OperationID  string                     `json:"operationId,omitempty"` // camelCase fix
+	Consumes     []string                   `json:"consumes,omitempty"`    // technically OpenAPI 2.0, not 3.0
+	Produces     []string                   `json:"produces,omitempty"`    // technically OpenAPI 2.0
+	Parameters   []*ParameterObject         `json:"parameters,omitempty"`
+	RequestBody  *RequestBodyObject         `json:"requestBody,omitempty"`
+	Schemes      []string                   `json:"schemes,omitempty"` // OpenAPI 2.0 field, not 3.0
+	Deprecated   bool                       `json:"deprecated,omitempty"`
+	Security     []map[string][]string      `json:"security,omitempty"`
+	ExternalDocs *ExternalDocsObject        `json:"externalDocs,omitempty"`
+	Responses    map[string]*ResponseObject `json:"responses"` // required
 }
 
 // -------- Parameter Object --------
 type ParameterObject struct {
-	Name        string        `json:"name"` // required by OpenAPI
-	In          string        `json:"in"`   // required by OpenAPI ("query", "header", "path", or "cookie")
-	Description string        `json:"description,omitempty"`
-	Default     string        `json:"default,omitempty"`
-	Required    bool          `json:"required,omitempty"`
-	Format      string        `json:"format,omitempty"`
-	Schema      *SchemaObject `json:"schema,omitempty"`
+	Name        string `json:"name"` // required by OpenAPI
+	In          string `json:"in"`   // required by OpenAPI ("query", "header", "path", or "cookie")
+	Description string `json:"description,omitempty"`
+	// Deprecated: defaults belong to the schema, use Schema.Default.
+	Default    string                    `json:"default,omitempty"`
+	Required   bool                      `json:"required,omitempty"`
+	Deprecated bool                      `json:"deprecated,omitempty"`
+	Format     string                    `json:"format,omitempty"`
+	Schema     *SchemaObject             `json:"schema,omitempty"`
+	Example    any                       `json:"example,omitempty"`
+	Examples   map[string]*ExampleObject `json:"examples,omitempty"`
+}
+
+// -------- Example Object --------
+type ExampleObject struct {
+	Summary       string `json:"summary,omitempty"`
+	Description   string `json:"description,omitempty"`
+	Value         any    `json:"value,omitempty"`
+	ExternalValue string `json:"externalValue,omitempty"`
 }
 
 type RequestBodyObject struct {
@@ -68,8 +98,9 @@ type RequestBodyObject struct {
 }
 
 type MediaTypeObject struct {
-	Schema  *SchemaObject `json:"schema,omitempty"`
-	Example any           `json:"example,omitempty"`
+	Schema   *SchemaObject             `json:"schema,omitempty"`
+	Example  any                       `json:"example,omitempty"`
+	Examples map[string]*ExampleObject `json:"examples,omitempty"`
 }
 
 type SchemasObject struct {
@@ -79,14 +110,26 @@ type SchemasObject struct {
 }
 
 type SchemaObject struct {
-	Type       string                   `json:"type,omitempty"`
-	Required   []string                 `json:"required,omitempty"`
-	Ref        string                   `json:"$ref,omitempty"` // Use $ref per OpenAPI spec
-	Example    any                      `json:"example,omitempty"`
-	Format     string                   `json:"format,omitempty"`
-	Enum       []string                 `json:"enum,omitempty"`
-	Items      *ItemsObject             `json:"items,omitempty"`
-	Properties map[string]*SchemaObject `json:"properties,omitempty"`
+	Type                 string                   `json:"type,omitempty"`
+	Title                string                   `json:"title,omitempty"`
+	Description          string                   `json:"description,omitempty"`
+	Required             []string                 `json:"required,omitempty"`
+	Ref                  string                   `json:"$ref,omitempty"` // Use $ref per OpenAPI spec
+	Example              any                      `json:"example,omitempty"`
+	Default              any                      `json:"default,omitempty"`
+	Format               string                   `json:"format,omitempty"`
+	Pattern              string                   `json:"pattern,omitempty"`
+	Minimum              *float64                 `json:"minimum,omitempty"`
+	Maximum              *float64                 `json:"maximum,omitempty"`
+	Enum                 []string                 `json:"enum,omitempty"`
+	Nullable             bool                     `json:"nullable,omitempty"`
+	ReadOnly             bool                     `json:"readOnly,omitempty"`
+	WriteOnly            bool                     `json:"writeOnly,omitempty"`
+	Deprecated           bool                     `json:"deprecated,omitempty"`
+	Items                *ItemsObject             `json:"items,omitempty"`
+	Properties           map[string]*SchemaObject `json:"properties,omitempty"`
+	AdditionalProperties *SchemaObject            `json:"additionalProperties,omitempty"` // value schema of map types
+	ExternalDocs         *ExternalDocsObject      `json:"externalDocs,omitempty"`
 }
 
 type ResponseObject struct {
@@ -95,26 +138,70 @@ type ResponseObject struct {
 }
 
 type ContentObject struct {
-	Schema *SchemaObject `json:"schema,omitempty"`
+	Schema   *SchemaObject             `json:"schema,omitempty"`
+	Examples map[string]*ExampleObject `json:"examples,omitempty"`
 }
 
 type ItemsObject struct {
-	Type       string                   `json:"type,omitempty"`
-	Format     string                   `json:"format,omitempty"`
-	Required   []string                 `json:"required,omitempty"`
-	Enum       []string                 `json:"enum,omitempty"`
-	Properties map[string]*SchemaObject `json:"properties,omitempty"`
+	Type                 string                   `json:"type,omitempty"`
+	Ref                  string                   `json:"$ref,omitempty"`
+	Format               string                   `json:"format,omitempty"`
+	Required             []string                 `json:"required,omitempty"`
+	Enum                 []string                 `json:"enum,omitempty"`
+	Items                *ItemsObject             `json:"items,omitempty"` // nested arrays
+	Properties           map[string]*SchemaObject `json:"properties,omitempty"`
+	AdditionalProperties *SchemaObject            `json:"additionalProperties,omitempty"`
+}
+
+// -------- Tag Object --------
+type TagObject struct {
+	Name         string              `json:"name"` // required
+	Description  string              `json:"description,omitempty"`
+	ExternalDocs *ExternalDocsObject `json:"externalDocs,omitempty"`
+}
+
+// TagGroupObject groups tags in the UI with the x-tagGroups extension.
+type TagGroupObject struct {
+	Name string   `json:"name"`
+	Tags []string `json:"tags"`
+}
+
+// -------- External Documentation Object --------
+type ExternalDocsObject struct {
+	Description string `json:"description,omitempty"`
+	Url         string `json:"url"` // required
 }
 
 // -------- Security Scheme Object --------
 type SecuritySchemeObject struct {
-	Type         string `json:"type,omitempty"`
-	Description  string `json:"description,omitempty"`
-	Name         string `json:"name,omitempty"`
-	In           string `json:"in,omitempty"`
-	Scheme       string `json:"scheme,omitempty"`
-	BearerFormat string `json:"bearerFormat,omitempty"`
-	Flow         string `json:"flow,omitempty"` // Possibly incomplete; OAuth2 may need flows object
+	Type        string `json:"type,omitempty"` // "apiKey", "http", "oauth2" or "openIdConnect"
+	Description string `json:"description,omitempty"`
+	// Name is the header, query or cookie name of an apiKey scheme. For the
+	// other types it is only used as the component name by AddSecurity.
+	Name             string            `json:"name,omitempty"`
+	In               string            `json:"in,omitempty"`
+	Scheme           string            `json:"scheme,omitempty"`
+	BearerFormat     string            `json:"bearerFormat,omitempty"`
+	Flows            *OAuthFlowsObject `json:"flows,omitempty"`
+	OpenIdConnectUrl string            `json:"openIdConnectUrl,omitempty"`
+	// Deprecated: OAuth2 flows are described by Flows.
+	Flow string `json:"flow,omitempty"`
+}
+
+// -------- OAuth Flows Object --------
+type OAuthFlowsObject struct {
+	Implicit          *OAuthFlowObject `json:"implicit,omitempty"`
+	Password          *OAuthFlowObject `json:"password,omitempty"`
+	ClientCredentials *OAuthFlowObject `json:"clientCredentials,omitempty"`
+	AuthorizationCode *OAuthFlowObject `json:"authorizationCode,omitempty"`
+}
+
+// -------- OAuth Flow Object --------
+type OAuthFlowObject struct {
+	AuthorizationUrl string            `json:"authorizationUrl,omitempty"` // implicit and authorizationCode
+	TokenUrl         string            `json:"tokenUrl,omitempty"`         // password, clientCredentials and authorizationCode
+	RefreshUrl       string            `json:"refreshUrl,omitempty"`
+	Scopes           map[string]string `json:"scopes"` // required, scope name to description
 }
 
 type HeaderObject struct {
@@ -142,16 +229,135 @@ type ServerObject struct {
 }
 
 type SpecBuilder struct {
-	Openapi    string           `json:"openapi"`
-	Info       *InfoObject      `json:"info"`
-	Schemes    []string         `json:"schemes,omitempty"`
-	Produces   []string         `json:"produces,omitempty"`
-	Consumes   []string         `json:"consumes,omitempty"`
-	Servers    []*ServerObject  `json:"servers,omitempty"`
-	Paths      PathObject       `json:"paths"`
-	Components *ComponentObject `json:"components,omitempty"`
+	Openapi      string                `json:"openapi"`
+	Info         *InfoObject           `json:"info"`
+	Schemes      []string              `json:"schemes,omitempty"`
+	Produces     []string              `json:"produces,omitempty"`
+	Consumes     []string              `json:"consumes,omitempty"`
+	Servers      []*ServerObject       `json:"servers,omitempty"`
+	Paths        PathObject            `json:"paths"`
+	Components   *ComponentObject      `json:"components,omitempty"`
+	Tags         []*TagObject          `json:"tags,omitempty"`
+	TagGroups    []*TagGroupObject     `json:"x-tagGroups,omitempty"`
+	ExternalDocs *ExternalDocsObject   `json:"externalDocs,omitempty"`
+	Security     []map[string][]string `json:"security,omitempty"`
+	// Diagnostics lists the problems found by the last ParsePaths call.
+	Diagnostics []Diagnostic `json:"-"`
+
+	schemaNaming   SchemaNamingStrategy
+	encoder        core.Encode
+	tagOrder       []string
+	declaredTags   []*TagObject
+	guardDetection bool
+	guardSchemes   []guardScheme
+	include        []RouteFilter
+	filters        []RouteFilter
+	versioning     *core.VersionOptions
+	versionTags    bool
 }
 
 type Config struct {
 	PersistAuthorization bool
+	// DocumentPath is the path serving the JSON document, the YAML document
+	// being served next to it with a .yaml extension. Defaults to
+	// <global prefix><ui path>/openapi.json.
+	DocumentPath string
+	// DocumentURL is the URL the UI loads the document from, when it differs
+	// from DocumentPath, e.g. behind a reverse proxy rewriting paths.
+	DocumentURL string
+	// UI selects the documentation UI. Defaults to SwaggerUI, SetUp and
+	// MountUI panic on an unknown UI.
+	UI UIType
+	// UIOptions are passed to the UI: the configuration object of Swagger
+	// UI, ReDoc and Scalar, or the element attributes of RapiDoc and
+	// Stoplight Elements.
+	UIOptions map[string]any
+	// Assets selects between the embedded Swagger UI assets, served under
+	// <ui path>/assets/, and the CDN.
+	Assets AssetSource
+	// AssetVersion is the version of the UI package loaded from the CDN.
+	// Defaults to SwaggerUIVersion for Swagger UI.
+	AssetVersion string
+
+	// Title is the title of the documentation page.
+	Title string
+	// Favicon is the URL of the page icon.
+	Favicon string
+	// CustomCSS is added to the page in a style element.
+	CustomCSS string
+	// CustomCSSUrl links a stylesheet from the page.
+	CustomCSSUrl string
+	// CustomJS is run once the page is loaded, from the init script of the
+	// page.
+	CustomJS string
+	// CustomJSUrl loads a script from the page.
+	CustomJSUrl string
+
+	// CSPNonce adds a nonce generated for each request to the scripts and
+	// styles of the page, to be allowed by ContentSecurityPolicy.
+	CSPNonce bool
+	// ContentSecurityPolicy is sent with the page, "{nonce}" being replaced
+	// with the nonce of the request. Defaults to a policy allowing the page
+	// assets when CSPNonce is set.
+	ContentSecurityPolicy string
+
+	// Enabled reports whether the documentation is served, and is called on
+	// each request. Endpoints answer 404 while it returns false.
+	Enabled func() bool
+	// BasicAuth protects the documentation with HTTP basic authentication.
+	BasicAuth *BasicAuth
+	// Guard protects the documentation with a tinhtinh guard.
+	Guard core.Guard
+	// Middleware wraps the documentation handlers.
+	Middleware func(http.Handler) http.Handler
+
+	// The options below only apply to Swagger UI.
+
+	// DocExpansion controls the default expansion of operations and tags:
+	// "list", "full" or "none".
+	DocExpansion string
+	// Filter enables the tag filter bar.
+	Filter bool
+	// TryItOutEnabled enables "Try it out" on every operation by default.
+	TryItOutEnabled bool
+	// DisplayRequestDuration shows the duration of "Try it out" requests.
+	DisplayRequestDuration bool
+	// DefaultModelsExpandDepth is the default expansion depth of the models
+	// section, -1 hiding it.
+	DefaultModelsExpandDepth *int
+	// SyntaxHighlight configures the highlighting of payloads.
+	SyntaxHighlight *SyntaxHighlightConfig
+	// SupportedSubmitMethods lists the HTTP methods "Try it out" is enabled
+	// for, e.g. "get" and "post".
+	SupportedSubmitMethods []string
+	// ValidatorUrl is the URL of the validator badge, "none" disabling it.
+	ValidatorUrl string
+	// InitOAuth configures the OAuth2 authorization dialog.
+	InitOAuth *OAuthConfig
+	// OAuth2RedirectUrl is the redirect URL of OAuth2 flows. Defaults to the
+	// oauth2-redirect.html page of the assets.
+	OAuth2RedirectUrl string
+	// RequestInterceptor is a JavaScript function called with each request
+	// and returning it, e.g. `(req) => { req.headers["X-Trace"] = "1"; return req; }`.
+	RequestInterceptor string
+	// ResponseInterceptor is a JavaScript function called with each response
+	// and returning it.
+	ResponseInterceptor string
+}
+
+type SyntaxHighlightConfig struct {
+	Activated bool   `json:"activated"`
+	Theme     string `json:"theme,omitempty"`
+}
+
+type OAuthConfig struct {
+	ClientId                                  string            `json:"clientId,omitempty"`
+	ClientSecret                              string            `json:"clientSecret,omitempty"`
+	Realm                                     string            `json:"realm,omitempty"`
+	AppName                                   string            `json:"appName,omitempty"`
+	ScopeSeparator                            string            `json:"scopeSeparator,omitempty"`
+	Scopes                                    []string          `json:"scopes,omitempty"`
+	AdditionalQueryStringParams               map[string]string `json:"additionalQueryStringParams,omitempty"`
+	UseBasicAuthenticationWithAccessCodeGrant bool              `json:"useBasicAuthenticationWithAccessCodeGrant,omitempty"`
+	UsePkceWithAuthorizationCodeGrant         bool              `json:"usePkceWithAuthorizationCodeGrant,omitempty"`
 }
@@ -0,0 +1,454 @@
+package swagger
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"html/template"
+	"io"
+	"net/http"
+	"slices"
+	"strings"
+
+	"github.com/tinh-tinh/tinhtinh/v2/core"
+)
+
+// UIType selects the documentation UI rendered by SetUp and MountUI.
+type UIType string
+
+const (
+	SwaggerUI         UIType = "swagger-ui"
+	ReDoc             UIType = "redoc"
+	Scalar            UIType = "scalar"
+	RapiDoc           UIType = "rapidoc"
+	StoplightElements UIType = "stoplight-elements"
+)
+
+// uiRenderer renders the documentation page of a UI.
+type uiRenderer struct {
+	// version is the default package version loaded from the CDN.
+	version string
+	// cdn is the jsDelivr URL of the package, formatted with the version.
+	cdn string
+	// title is the default page title.
+	title    string
+	template *template.Template
+	// options returns the options given to the UI.
+	options func(page *uiPage) map[string]any
+	// script returns the init script of the UI, if it needs one.
+	script func(config Config) string
+}
+
+// uiPage is the data of the page templates.
+type uiPage struct {
+	Title        string
+	Favicon      string
+	Assets       string
+	DocumentURL  string
+	Documents    []documentLink
+	Options      map[string]any
+	InitOAuth    *OAuthConfig
+	InitScript   string
+	CustomCSS    template.CSS
+	CustomCSSUrl string
+	CustomJSUrl  string
+	Nonce        string
+
+	config Config
+}
+
+var uiRenderers = map[UIType]uiRenderer{
+	SwaggerUI: {
+		version:  SwaggerUIVersion,
+		cdn:      "https://cdn.jsdelivr.net/npm/swagger-ui-dist@%s",
+		title:    "Swagger UI",
+		template: parseUITemplate(swaggerUITemplate),
+		options:  swaggerUIOptions,
+		script:   swaggerUIScript,
+	},
+	ReDoc: {
+		version:  "2.1.5",
+		cdn:      "https://cdn.jsdelivr.net/npm/redoc@%s/bundles",
+		title:    "ReDoc",
+		template: parseUITemplate(reDocTemplate),
+		options:  defaultUIOptions(nil),
+		script:   reDocScript,
+	},
+	Scalar: {
+		version:  "1.24.0",
+		cdn:      "https://cdn.jsdelivr.net/npm/@scalar/api-reference@%s/dist/browser",
+		title:    "API Reference",
+		template: parseUITemplate(scalarTemplate),
+		options:  scalarOptions,
+	},
+	RapiDoc: {
+		version:  "9.3.4",
+		cdn:      "https://cdn.jsdelivr.net/npm/rapidoc@%s/dist",
+		title:    "RapiDoc",
+		template: parseUITemplate(rapiDocTemplate + elementTemplate("rapi-doc")),
+		options:  rapiDocOptions,
+		script:   elementScript,
+	},
+	StoplightElements: {
+		version:  "8.1.0",
+		cdn:      "https://cdn.jsdelivr.net/npm/@stoplight/elements@%s",
+		title:    "API Reference",
+		template: parseUITemplate(stoplightElementsTemplate + elementTemplate("elements-api")),
+		options:  stoplightElementsOptions,
+		script:   elementScript,
+	},
+}
+
+// MountUI serves a documentation page at <global prefix><path>, loading the
+// document from documentURL.
+//
+// SetUp already mounts a UI next to the document it serves; MountUI adds
+// other ones over the same document:
+//
+//	swagger.SetUp("/swagger", app, spec)
+//	swagger.MountUI("/redoc", app, "/api/swagger/openapi.json", swagger.Config{UI: swagger.ReDoc})
+func MountUI(path string, app *core.App, documentURL string, configs ...Config) {
+	var config Config
+	if len(configs) > 0 {
+		config = configs[0]
+	}
+	route := fmt.Sprintf("%s%s", core.IfSlashPrefixString(app.Prefix), core.IfSlashPrefixString(path))
+	mountUI(route, app, []documentLink{{URL: documentURL}}, config)
+}
+
+// documentLink is a document listed by the UI.
+type documentLink struct {
+	Name string `json:"name"`
+	URL  string `json:"url"`
+}
+
+// mountUI serves the UI page at route, listing the given documents. Only
+// Swagger UI can switch between several documents, other UIs showing the
+// first one. It panics when the configured UI is unknown.
+func mountUI(route string, app *core.App, documents []documentLink, config Config) {
+	ui := config.UI
+	if ui == "" {
+		ui = SwaggerUI
+	}
+	renderer, ok := uiRenderers[ui]
+	if !ok {
+		// A misspelled UI would silently leave the app without documentation
+		names := make([]string, 0, len(uiRenderers))
+		for name := range uiRenderers {
+			names = append(names, string(name))
+		}
+		slices.Sort(names)
+		panic(fmt.Sprintf("swagger: unknown UI %q, expected one of %s", ui, strings.Join(names, ", ")))
+	}
+
+	assets := renderer.assets(config)
+	if ui == SwaggerUI && config.Assets == EmbeddedAssets {
+		assets = route + "/assets"
+		config.handle(app, route+"/assets/", assetsHandler(route+"/assets/"))
+	}
+
+	page := uiPage{
+		Title:        config.Title,
+		Favicon:      config.Favicon,
+		Assets:       assets,
+		DocumentURL:  documents[0].URL,
+		Documents:    documents,
+		InitOAuth:    config.InitOAuth,
+		CustomCSS:    template.CSS(config.CustomCSS),
+		CustomCSSUrl: config.CustomCSSUrl,
+		CustomJSUrl:  config.CustomJSUrl,
+		config:       config,
+	}
+	if page.Title == "" {
+		page.Title = renderer.title
+	}
+	page.Options = renderer.options(&page)
+
+	var script string
+	if renderer.script != nil {
+		script = renderer.script(config)
+	}
+	if config.CustomJS != "" {
+		script += config.CustomJS + "\n"
+	}
+	if script != "" {
+		page.InitScript = route + "/init.js"
+		config.handle(app, page.InitScript, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			writeDocument(w, "text/javascript; charset=utf-8", []byte(script))
+		}))
+	}
+
+	if err := renderer.template.Execute(io.Discard, page); err != nil {
+		fmt.Println(err)
+		return
+	}
+
+	policy := config.ContentSecurityPolicy
+	if policy == "" && config.CSPNonce {
+		policy = defaultContentSecurityPolicy(assets)
+	}
+	config.handle(app, route, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		page := page
+		if config.CSPNonce {
+			nonce, err := newNonce()
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusInternalServerError)
+				return
+			}
+			page.Nonce = nonce
+		}
+		var html bytes.Buffer
+		if err := renderer.template.Execute(&html, page); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		if policy != "" {
+			w.Header().Set("Content-Security-Policy", strings.ReplaceAll(policy, "{nonce}", page.Nonce))
+		}
+		writeDocument(w, "text/html; charset=utf-8", html.Bytes())
+	}))
+}
+
+// assets returns the CDN base URL of the UI package, without trailing
+// slash.
+func (renderer uiRenderer) assets(config Config) string {
+	version := config.AssetVersion
+	if version == "" {
+		version = renderer.version
+	}
+	return fmt.Sprintf(renderer.cdn, version)
+}
+
+// defaultUIOptions returns an options function merging Config.UIOptions
+// over defaults.
+func defaultUIOptions(defaults map[string]any) func(page *uiPage) map[string]any {
+	return func(page *uiPage) map[string]any {
+		options := make(map[string]any, len(defaults)+len(page.config.UIOptions))
+		for k, v := range defaults {
+			options[k] = v
+		}
+		for k, v := range page.config.UIOptions {
+			options[k] = v
+		}
+		return options
+	}
+}
+
+func swaggerUIOptions(page *uiPage) map[string]any {
+	config := page.config
+	options := defaultUIOptions(nil)(page)
+	if len(page.Documents) > 1 {
+		options["urls"] = page.Documents
+	} else {
+		options["url"] = page.DocumentURL
+	}
+	if config.PersistAuthorization {
+		options["persistAuthorization"] = true
+	}
+	if config.DocExpansion != "" {
+		options["docExpansion"] = config.DocExpansion
+	}
+	if config.Filter {
+		options["filter"] = true
+	}
+	if config.TryItOutEnabled {
+		options["tryItOutEnabled"] = true
+	}
+	if config.DisplayRequestDuration {
+		options["displayRequestDuration"] = true
+	}
+	if config.DefaultModelsExpandDepth != nil {
+		options["defaultModelsExpandDepth"] = *config.DefaultModelsExpandDepth
+	}
+	if config.SyntaxHighlight != nil {
+		options["syntaxHighlight"] = config.SyntaxHighlight
+	}
+	if config.SupportedSubmitMethods != nil {
+		options["supportedSubmitMethods"] = config.SupportedSubmitMethods
+	}
+	if config.ValidatorUrl != "" {
+		options["validatorUrl"] = config.ValidatorUrl
+	}
+	if config.OAuth2RedirectUrl != "" {
+		options["oauth2RedirectUrl"] = config.OAuth2RedirectUrl
+	} else if config.Assets == EmbeddedAssets {
+		options["oauth2RedirectUrl"] = page.Assets + "/oauth2-redirect.html"
+	}
+	return options
+}
+
+func scalarOptions(page *uiPage) map[string]any {
+	defaults := map[string]any{}
+	if page.config.PersistAuthorization {
+		defaults["persistAuth"] = true
+	}
+	return defaultUIOptions(defaults)(page)
+}
+
+func rapiDocOptions(page *uiPage) map[string]any {
+	defaults := map[string]any{}
+	if page.config.PersistAuthorization {
+		defaults["persist-auth"] = "true"
+	}
+	options := defaultUIOptions(defaults)(page)
+	options["spec-url"] = page.DocumentURL
+	return options
+}
+
+func stoplightElementsOptions(page *uiPage) map[string]any {
+	options := defaultUIOptions(map[string]any{"router": "hash"})(page)
+	options["apiDescriptionUrl"] = page.DocumentURL
+	return options
+}
+
+func parseUITemplate(body string) *template.Template {
+	funcs := template.FuncMap{"json": jsonString}
+	return template.Must(template.Must(template.New("layout").Funcs(funcs).Parse(uiLayoutTemplate)).Parse(body))
+}
+
+// jsonString encodes v as JSON, for use in attributes.
+func jsonString(v any) (string, error) {
+	data, err := json.Marshal(v)
+	return string(data), err
+}
+
+// uiLayoutTemplate is the page shared by all UIs, which define the head and
+// body templates. Pages have no inline script: UIs read their options from
+// data attributes in the init script, so that they work under a strict
+// Content-Security-Policy.
+const uiLayoutTemplate = `<!DOCTYPE html>
+<html lang="en">
+<head>
+    <meta charset="UTF-8">
+    <meta name="viewport" content="width=device-width, initial-scale=1.0">
+    <title>{{.Title}}</title>
+    {{- if .Favicon}}
+    <link rel="icon" href="{{.Favicon}}">
+    {{- end}}
+    {{- template "head" .}}
+    {{- if .CustomCSSUrl}}
+    <link rel="stylesheet" type="text/css" href="{{.CustomCSSUrl}}"{{if .Nonce}} nonce="{{.Nonce}}"{{end}}>
+    {{- end}}
+    {{- if .CustomCSS}}
+    <style{{if .Nonce}} nonce="{{.Nonce}}"{{end}}>{{.CustomCSS}}</style>
+    {{- end}}
+</head>
+<body>
+    {{- template "body" .}}
+    {{- if .CustomJSUrl}}
+    <script src="{{.CustomJSUrl}}"{{if .Nonce}} nonce="{{.Nonce}}"{{end}}></script>
+    {{- end}}
+    {{- if .InitScript}}
+    <script src="{{.InitScript}}"{{if .Nonce}} nonce="{{.Nonce}}"{{end}}></script>
+    {{- end}}
+</body>
+</html>
+`
+
+const swaggerUITemplate = `
+{{define "head"}}
+    {{- if not .Favicon}}
+    <link rel="icon" type="image/png" href="{{.Assets}}/favicon-32x32.png" sizes="32x32">
+    <link rel="icon" type="image/png" href="{{.Assets}}/favicon-16x16.png" sizes="16x16">
+    {{- end}}
+    <link rel="stylesheet" type="text/css" href="{{.Assets}}/swagger-ui.css"{{if .Nonce}} nonce="{{.Nonce}}"{{end}}>
+    <script src="{{.Assets}}/swagger-ui-bundle.js"{{if .Nonce}} nonce="{{.Nonce}}"{{end}}></script>
+    <script src="{{.Assets}}/swagger-ui-standalone-preset.js"{{if .Nonce}} nonce="{{.Nonce}}"{{end}}></script>
+{{- end}}
+{{define "body"}}
+    <div id="swagger-ui" data-config="{{json .Options}}"{{if .InitOAuth}} data-oauth="{{json .InitOAuth}}"{{end}}></div>
+{{- end}}
+`
+
+const reDocTemplate = `
+{{define "head"}}
+    <script src="{{.Assets}}/redoc.standalone.js"{{if .Nonce}} nonce="{{.Nonce}}"{{end}}></script>
+{{- end}}
+{{define "body"}}
+    <div id="redoc" data-url="{{.DocumentURL}}" data-config="{{json .Options}}"></div>
+{{- end}}
+`
+
+const scalarTemplate = `
+{{define "head"}}{{end}}
+{{define "body"}}
+    <script id="api-reference" data-url="{{.DocumentURL}}" data-configuration="{{json .Options}}"{{if .Nonce}} nonce="{{.Nonce}}"{{end}}></script>
+    <script src="{{.Assets}}/standalone.js"{{if .Nonce}} nonce="{{.Nonce}}"{{end}}></script>
+{{- end}}
+`
+
+// elementTemplate renders the root of web component UIs, the init script
+// creating the element with options as attributes.
+func elementTemplate(name string) string {
+	return `
+{{define "body"}}
+    <div id="ui-root" data-element="` + name + `" data-config="{{json .Options}}"></div>
+{{- end}}
+`
+}
+
+const rapiDocTemplate = `
+{{define "head"}}
+    <script type="module" src="{{.Assets}}/rapidoc-min.js"{{if .Nonce}} nonce="{{.Nonce}}"{{end}}></script>
+{{- end}}
+`
+
+const stoplightElementsTemplate = `
+{{define "head"}}
+    <script src="{{.Assets}}/web-components.min.js"{{if .Nonce}} nonce="{{.Nonce}}"{{end}}></script>
+    <link rel="stylesheet" href="{{.Assets}}/styles.min.css"{{if .Nonce}} nonce="{{.Nonce}}"{{end}}>
+{{- end}}
+`
+
+// swaggerUIScript starts Swagger UI, adding the interceptors which are
+// JavaScript functions and cannot be passed as JSON.
+func swaggerUIScript(config Config) string {
+	var interceptors string
+	if config.RequestInterceptor != "" {
+		interceptors += "\n        requestInterceptor: " + config.RequestInterceptor + ","
+	}
+	if config.ResponseInterceptor != "" {
+		interceptors += "\n        responseInterceptor: " + config.ResponseInterceptor + ","
+	}
+	return `(function () {
+    const root = document.getElementById("swagger-ui");
+    const config = JSON.parse(root.dataset.config);
+    // Only the top bar of the standalone layout reads urls, to switch
+    // between documents.
+    const standalone = Array.isArray(config.urls);
+    const ui = SwaggerUIBundle(Object.assign({
+        dom_id: "#swagger-ui",
+        deepLinking: true,
+        presets: standalone
+            ? [SwaggerUIBundle.presets.apis, SwaggerUIStandalonePreset]
+            : [SwaggerUIBundle.presets.apis],
+        plugins: standalone ? [SwaggerUIBundle.plugins.DownloadUrl] : [],
+        layout: standalone ? "StandaloneLayout" : "BaseLayout",` + interceptors + `
+    }, config));
+    if (root.dataset.oauth) {
+        ui.initOAuth(JSON.parse(root.dataset.oauth));
+    }
+    window.ui = ui;
+})();
+`
+}
+
+func reDocScript(Config) string {
+	return `(function () {
+    const root = document.getElementById("redoc");
+    Redoc.init(root.dataset.url, JSON.parse(root.dataset.config), root);
+})();
+`
+}
+
+func elementScript(Config) string {
+	return `(function () {
+    const root = document.getElementById("ui-root");
+    const element = document.createElement(root.dataset.element);
+    for (const [key, value] of Object.entries(JSON.parse(root.dataset.config))) {
+        element.setAttribute(key, typeof value === "string" ? value : JSON.stringify(value));
+    }
+    root.appendChild(element);
+})();
+`
+}
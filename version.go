@@ -0,0 +1,106 @@
+package swagger
+
+import (
+	"fmt"
+	"reflect"
+	"slices"
+
+	"github.com/tinh-tinh/tinhtinh/v2/core"
+)
+
+// SetVersioning sets the versioning of the app routes, as given to
+// core.App.EnableVersioning. It is read from the app when not set.
+func (spec *SpecBuilder) SetVersioning(opt core.VersionOptions) *SpecBuilder {
+	spec.versioning = &opt
+	return spec
+}
+
+// ForVersion restricts the document to the routes of the given API version
+// and the routes without version, which serve every version. With header or
+// media type versioning, several versions of an operation share the same
+// path, so each version needs its own document.
+func (spec *SpecBuilder) ForVersion(version string) *SpecBuilder {
+	return spec.Filter(ByVersion(version))
+}
+
+// SetVersionTags tags the operations of versioned routes with their
+// version, e.g. "v1", to tell versions apart in a combined document.
+func (spec *SpecBuilder) SetVersionTags(enabled bool) *SpecBuilder {
+	spec.versionTags = enabled
+	return spec
+}
+
+// ByVersion matches routes of one of the given versions, and routes without
+// version.
+func ByVersion(versions ...string) RouteFilter {
+	return func(route *core.Router) bool {
+		return route.Version == "" || slices.Contains(versions, route.Version)
+	}
+}
+
+// versioningOf returns the versioning of the app routes, or nil when
+// versioning is disabled or cannot be read from the app.
+func (spec *SpecBuilder) versioningOf(app *core.App) (*core.VersionOptions, error) {
+	if spec.versioning != nil {
+		return spec.versioning, nil
+	}
+	return readVersioning(reflect.ValueOf(app).Elem())
+}
+
+// readVersioning reads the version field of an app struct: a pointer to a
+// struct with the Type, Header and Key string fields of core.VersionOptions,
+// nil when versioning is disabled.
+func readVersioning(app reflect.Value) (*core.VersionOptions, error) {
+	field := app.FieldByName("version")
+	if !field.IsValid() || field.Kind() != reflect.Pointer || field.Type().Elem().Kind() != reflect.Struct {
+		return nil, fmt.Errorf("cannot read the versioning of %s", app.Type())
+	}
+	if field.IsNil() {
+		return nil, nil
+	}
+
+	version := field.Elem()
+	var typ, header, key string
+	for name, dst := range map[string]*string{"Type": &typ, "Header": &header, "Key": &key} {
+		f := version.FieldByName(name)
+		if !f.IsValid() || f.Kind() != reflect.String {
+			return nil, fmt.Errorf("cannot read the versioning of %s", app.Type())
+		}
+		*dst = f.String()
+	}
+	opt := core.VersionOptions{Type: core.VersionType(typ), Header: header, Key: key}
+	return &opt, nil
+}
+
+// applyVersion documents how the version of a versioned route is selected:
+// a header parameter for header versioning, or the media types of the
+// responses for media type versioning. URI versions are part of the path.
+func applyVersion(operation *OperationObject, route *core.Router, versioning *core.VersionOptions) {
+	if versioning == nil || route.Version == "" {
+		return
+	}
+	switch versioning.Type {
+	case core.HeaderVersion:
+		operation.Parameters = append(operation.Parameters, &ParameterObject{
+			Name:        versioning.Header,
+			In:          "header",
+			Description: "API version",
+			Required:    true,
+			Schema: &SchemaObject{
+				Type: "string",
+				Enum: []string{route.Version},
+			},
+		})
+	case core.MediaTypeVersion:
+		mediaType := "application/json;" + versioning.Key + route.Version
+		for _, response := range operation.Responses {
+			if response.Content == nil {
+				response.Content = map[string]*ContentObject{"application/json": {}}
+			}
+			if content, ok := response.Content["application/json"]; ok {
+				delete(response.Content, "application/json")
+				response.Content[mediaType] = content
+			}
+		}
+	}
+}
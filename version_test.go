@@ -0,0 +1,113 @@
+package swagger_test
+
+import (
+	"maps"
+	"reflect"
+	"slices"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/tinh-tinh/swagger/v2"
+	"github.com/tinh-tinh/tinhtinh/v2/core"
+)
+
+func versionedUsersController(version string) core.Controllers {
+	return func(module core.Module) core.Controller {
+		ctrl := module.NewController("Users").Version(version).Metadata(swagger.ApiTag("User")).Registry()
+
+		ctrl.Metadata(swagger.ApiOkResponse([]Response{})).Get("", func(ctx core.Ctx) error {
+			return ctx.JSON(core.Map{"version": version})
+		})
+
+		return ctrl
+	}
+}
+
+func versionedAppModule() core.Module {
+	return core.NewModule(core.NewModuleOptions{
+		Imports: []core.Modules{PostModule},
+		Controllers: []core.Controllers{
+			versionedUsersController("1"),
+			versionedUsersController("2"),
+		},
+	})
+}
+
+func Test_URIVersioning(t *testing.T) {
+	app := core.CreateFactory(versionedAppModule)
+	app.SetGlobalPrefix("api")
+	app.EnableVersioning(core.VersionOptions{Type: core.URIVersion})
+
+	spec := swagger.NewSpecBuilder().SetVersionTags(true)
+	spec.ParsePaths(app)
+	require.Equal(t, []string{"/api/posts", "/api/posts/{id}", "/api/users/v1", "/api/users/v2"}, slices.Sorted(maps.Keys(spec.Paths)))
+	require.Equal(t, []string{"User", "v1"}, spec.Paths["/api/users/v1"].Get.Tags)
+	require.Equal(t, []string{"Post"}, spec.Paths["/api/posts"].Get.Tags)
+	require.Empty(t, spec.Diagnostics)
+
+	spec = swagger.NewSpecBuilder().ForVersion("2")
+	spec.ParsePaths(app)
+	require.Equal(t, []string{"/api/posts", "/api/posts/{id}", "/api/users/v2"}, slices.Sorted(maps.Keys(spec.Paths)))
+}
+
+func Test_HeaderVersioning(t *testing.T) {
+	app := core.CreateFactory(versionedAppModule)
+	app.SetGlobalPrefix("api")
+	app.EnableVersioning(core.VersionOptions{Type: core.HeaderVersion, Header: "X-Api-Version"})
+
+	spec := swagger.NewSpecBuilder()
+	spec.ParsePaths(app)
+	require.Equal(t, []string{"/api/posts", "/api/posts/{id}", "/api/users"}, slices.Sorted(maps.Keys(spec.Paths)))
+	require.Len(t, spec.Diagnostics, 1)
+	require.Equal(t, swagger.DiagnosticRouteConflict, spec.Diagnostics[0].Kind)
+	require.Equal(t, "GET /api/users", spec.Diagnostics[0].Route)
+
+	spec = swagger.NewSpecBuilder().ForVersion("1")
+	spec.ParsePaths(app)
+	require.Empty(t, spec.Diagnostics)
+	params := spec.Paths["/api/users"].Get.Parameters
+	require.Len(t, params, 1)
+	require.Equal(t, "X-Api-Version", params[0].Name)
+	require.Equal(t, "header", params[0].In)
+	require.True(t, params[0].Required)
+	require.Equal(t, []string{"1"}, params[0].Schema.Enum)
+	require.Empty(t, spec.Paths["/api/posts"].Get.Parameters)
+}
+
+func Test_MediaTypeVersioning(t *testing.T) {
+	app := core.CreateFactory(versionedAppModule)
+	app.SetGlobalPrefix("api")
+
+	spec := swagger.NewSpecBuilder().
+		SetVersioning(core.VersionOptions{Type: core.MediaTypeVersion, Key: "v="}).
+		ForVersion("2")
+	spec.ParsePaths(app)
+	content := spec.Paths["/api/users"].Get.Responses["200"].Content
+	require.Equal(t, []string{"application/json;v=2"}, slices.Collect(maps.Keys(content)))
+	require.Equal(t, "array", content["application/json;v=2"].Schema.Type)
+}
+
+func Test_ReadVersioning(t *testing.T) {
+	app := core.CreateFactory(versionedAppModule)
+	versioning, err := swagger.ReadVersioning(reflect.ValueOf(app).Elem())
+	require.Nil(t, err)
+	require.Nil(t, versioning)
+
+	app.EnableVersioning(core.VersionOptions{Type: core.HeaderVersion, Header: "X-Api-Version"})
+	versioning, err = swagger.ReadVersioning(reflect.ValueOf(app).Elem())
+	require.Nil(t, err)
+	require.Equal(t, &core.VersionOptions{Type: core.HeaderVersion, Header: "X-Api-Version"}, versioning)
+
+	// A refactored app is reported instead of read
+	type renamed struct {
+		versioning *core.VersionOptions
+	}
+	_, err = swagger.ReadVersioning(reflect.ValueOf(renamed{}))
+	require.NotNil(t, err)
+
+	type reshaped struct {
+		version *struct{ Type core.VersionType }
+	}
+	_, err = swagger.ReadVersioning(reflect.ValueOf(reshaped{version: &struct{ Type core.VersionType }{}}))
+	require.NotNil(t, err)
+}
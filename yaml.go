@@ -0,0 +1,73 @@
+package swagger
+
+import (
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// YAML returns the document encoded as YAML, as served next to the JSON
+// document returned by Document.
+func (spec *SpecBuilder) YAML() ([]byte, error) {
+	data, err := spec.Document()
+	if err != nil {
+		return nil, err
+	}
+	return jsonToYAML(data)
+}
+
+// jsonToYAML converts a JSON document to YAML. JSON being valid YAML, the
+// document is decoded into a node tree, which keeps the key order, and
+// encoded again in block style.
+func jsonToYAML(data []byte) ([]byte, error) {
+	var node yaml.Node
+	if err := yaml.Unmarshal(data, &node); err != nil {
+		return nil, err
+	}
+	resetStyle(&node)
+	return yaml.Marshal(&node)
+}
+
+func resetStyle(node *yaml.Node) {
+	node.Style = 0
+	for _, child := range node.Content {
+		resetStyle(child)
+	}
+}
+
+// acceptsYAML reports whether the request prefers a YAML response: a YAML
+// media type of the Accept header must have a higher quality than any JSON
+// media type or wildcard, the first one listed winning ties.
+func acceptsYAML(r *http.Request) bool {
+	var best float64
+	prefersYAML := false
+	for _, accept := range strings.Split(r.Header.Get("Accept"), ",") {
+		mediaType, params, err := mime.ParseMediaType(strings.TrimSpace(accept))
+		if err != nil {
+			continue
+		}
+		q := 1.0
+		if value, ok := params["q"]; ok {
+			if q, err = strconv.ParseFloat(value, 64); err != nil {
+				continue
+			}
+		}
+
+		var isYAML bool
+		switch {
+		case strings.HasSuffix(mediaType, "/yaml"), strings.HasSuffix(mediaType, "/x-yaml"):
+			isYAML = true
+		case strings.HasSuffix(mediaType, "/json"), strings.HasSuffix(mediaType, "/*"):
+			isYAML = false
+		default:
+			continue
+		}
+		if q > best {
+			best, prefersYAML = q, isYAML
+		}
+	}
+	return prefersYAML
+}
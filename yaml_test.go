@@ -0,0 +1,92 @@
+package swagger_test
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tinh-tinh/swagger/v2"
+	"github.com/tinh-tinh/tinhtinh/v2/core"
+)
+
+func Test_SpecYAML(t *testing.T) {
+	spec := swagger.NewSpecBuilder().SetTitle("Orders").SetVersion("2")
+	spec.Components.Schemas["Order"] = &swagger.SchemaObject{
+		Type: "object",
+		Properties: map[string]*swagger.SchemaObject{
+			"id":     {Type: "string", Example: "1"},
+			"active": {Type: "boolean", Example: true},
+		},
+	}
+
+	data, err := spec.YAML()
+	require.Nil(t, err)
+
+	doc := string(data)
+	assert.True(t, strings.HasPrefix(doc, "components:\n"))
+	assert.Contains(t, doc, "\nopenapi: 3.0.0\n")
+	assert.Contains(t, doc, "    title: Orders\n")
+	assert.Contains(t, doc, "    version: \"2\"\n")
+	assert.Contains(t, doc, "                    example: \"1\"\n")
+	assert.Contains(t, doc, "                    example: true\n")
+	assert.NotContains(t, doc, "{")
+}
+
+func Test_YAML(t *testing.T) {
+	server := core.CreateFactory(AppModule)
+	server.SetGlobalPrefix("api")
+
+	document := swagger.NewSpecBuilder()
+	swagger.SetUp("/swagger", server, document)
+	testServer := httptest.NewServer(server.PrepareBeforeListen())
+	defer testServer.Close()
+
+	testClient := testServer.Client()
+	resp, err := testClient.Get(testServer.URL + "/api/swagger/openapi.yaml")
+	require.Nil(t, err)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	require.Equal(t, "application/yaml", resp.Header.Get("Content-Type"))
+	data, err := io.ReadAll(resp.Body)
+	require.Nil(t, err)
+	require.Contains(t, string(data), "openapi: 3.0.0")
+
+	req, err := http.NewRequest("GET", testServer.URL+"/api/swagger/openapi.json", nil)
+	require.Nil(t, err)
+	req.Header.Set("Accept", "application/yaml")
+	resp, err = testClient.Do(req)
+	require.Nil(t, err)
+	require.Equal(t, "application/yaml", resp.Header.Get("Content-Type"))
+
+	resp, err = testClient.Get(testServer.URL + "/api/swagger/openapi.json")
+	require.Nil(t, err)
+	require.Equal(t, "application/json", resp.Header.Get("Content-Type"))
+}
+
+func Test_AcceptsYAML(t *testing.T) {
+	cases := map[string]bool{
+		"":                                       false,
+		"application/json":                       false,
+		"application/yaml":                       true,
+		"application/x-yaml":                     true,
+		"text/yaml; charset=utf-8":               true,
+		"text/html, application/yaml;q=0.9":      true,
+		"application/json, application/yaml":     false,
+		"*/*":                                    false,
+		"application/yaml;q=0, application/json": false,
+		"application/yaml;q=0":                   false,
+		"application/json;q=0.5, application/yaml":    true,
+		"application/yaml;q=0.5, application/json":    false,
+		"application/yaml, */*;q=0.1":                 true,
+		"application/yaml;q=0.8, application/*;q=0.9": false,
+		"application/yaml;q=bad, application/json":    false,
+	}
+	for accept, expected := range cases {
+		r := httptest.NewRequest("GET", "/openapi.json", nil)
+		r.Header.Set("Accept", accept)
+		assert.Equal(t, expected, swagger.AcceptsYAML(r), accept)
+	}
+}